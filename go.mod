@@ -1,6 +1,6 @@
 module github.com/terraform-redhat/terraform-provider-ocm
 
-go 1.18
+go 1.21
 
 require (
 	github.com/hashicorp/go-version v1.6.0
@@ -9,7 +9,7 @@ require (
 	github.com/hashicorp/terraform-plugin-go v0.5.0
 	github.com/onsi/ginkgo/v2 v2.4.0
 	github.com/onsi/gomega v1.23.0
-	github.com/openshift-online/ocm-sdk-go v0.1.315
+	github.com/openshift-online/ocm-sdk-go v0.1.380
 	github.com/segmentio/ksuid v1.0.4
 	k8s.io/apimachinery v0.26.1
 )
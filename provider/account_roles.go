@@ -0,0 +1,166 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	defaultAccountRolePath = "/"
+	maxRoleNameLength      = 64
+)
+
+var (
+	accountRolePrefixRE = regexp.MustCompile(`^[\w+=,.@-]+$`)
+	accountRolePathRE   = regexp.MustCompile(`^/$|^/[\x21-\x7E]+/$`)
+	awsAccountIDRE      = regexp.MustCompile(`^\d{12}$`)
+)
+
+// accountRole describes one of the account roles of a ROSA cluster: the attribute that contains
+// its ARN, the suffix added to the account role prefix to build its name and the type used by OCM
+// to identify its policies.
+type accountRole struct {
+	path     *tftypes.AttributePath
+	suffix   string
+	roleType string
+}
+
+// accountRoles are the account roles that can be derived from the account role prefix.
+var accountRoles = []accountRole{
+	{
+		path:     tftypes.NewAttributePath().WithAttributeName("sts").WithAttributeName("role_arn"),
+		suffix:   "Installer-Role",
+		roleType: "installer",
+	},
+	{
+		path:     tftypes.NewAttributePath().WithAttributeName("sts").WithAttributeName("support_role_arn"),
+		suffix:   "Support-Role",
+		roleType: "support",
+	},
+	{
+		path: tftypes.NewAttributePath().WithAttributeName("sts").
+			WithAttributeName("instance_iam_roles").WithAttributeName("master_role_arn"),
+		suffix:   "ControlPlane-Role",
+		roleType: "instance_controlplane",
+	},
+	{
+		path: tftypes.NewAttributePath().WithAttributeName("sts").
+			WithAttributeName("instance_iam_roles").WithAttributeName("worker_role_arn"),
+		suffix:   "Worker-Role",
+		roleType: "instance_worker",
+	},
+}
+
+// accountRoleName returns the name of an account role, for example
+// 'ManagedOpenShift-Installer-Role'.
+func accountRoleName(prefix string, role accountRole) string {
+	return fmt.Sprintf("%s-%s", prefix, role.suffix)
+}
+
+// accountRolePolicyName returns the name of the permission policy of an account role, for example
+// 'ManagedOpenShift-Installer-Role-Policy'.
+func accountRolePolicyName(prefix string, role accountRole) string {
+	return fmt.Sprintf("%s-Policy", accountRoleName(prefix, role))
+}
+
+// accountRoleARN returns the ARN of an account role, for example
+// 'arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role'.
+func accountRoleARN(partition, accountID, path, prefix string, role accountRole) string {
+	return roleARN(partition, accountID, path, accountRoleName(prefix, role))
+}
+
+// roleARN returns the ARN of the IAM role with the given name and path, for example
+// 'arn:aws:iam::123456789012:role/rosa/my-role'.
+func roleARN(partition, accountID, path, name string) string {
+	if path == "" {
+		path = defaultAccountRolePath
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:role%s%s", partition, accountID, path, name)
+}
+
+// maxAccountRolePrefixLength returns the maximum length of the account role prefix, so that the
+// names of all the account roles fit in the limit of AWS.
+func maxAccountRolePrefixLength() int {
+	result := maxRoleNameLength
+	for _, role := range accountRoles {
+		length := maxRoleNameLength - len(accountRoleName("", role))
+		if length < result {
+			result = length
+		}
+	}
+	return result
+}
+
+// modifyPlanAccountRoles is used by the ROSA cluster resource to fill in the plan the ARNs of the
+// account roles that aren't explicitly set in the configuration, deriving them from the account
+// role prefix, so that they are visible in the plan and saved in the state.
+func modifyPlanAccountRoles(ctx context.Context, request tfsdk.ModifyResourcePlanRequest,
+	response *tfsdk.ModifyResourcePlanResponse) {
+	// Nothing to do when the resource is being destroyed:
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+
+	stsPath := tftypes.NewAttributePath().WithAttributeName("sts")
+	var accountID, region, prefix, path types.String
+	targets := map[*tftypes.AttributePath]interface{}{
+		tftypes.NewAttributePath().WithAttributeName("aws_account_id"): &accountID,
+		tftypes.NewAttributePath().WithAttributeName("cloud_region"):   &region,
+		stsPath.WithAttributeName("account_role_prefix"):               &prefix,
+		stsPath.WithAttributeName("path"):                              &path,
+	}
+	for attributePath, target := range targets {
+		diags := request.Config.GetAttribute(ctx, attributePath, target)
+		response.Diagnostics.Append(diags...)
+	}
+	if response.Diagnostics.HasError() {
+		return
+	}
+	if !isStringSet(prefix) || !isStringSet(accountID) || !isStringSet(region) || path.Unknown {
+		return
+	}
+
+	// Explicit values in the configuration take precedence:
+	for _, role := range accountRoles {
+		var configured types.String
+		diags := request.Config.GetAttribute(ctx, role.path, &configured)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+		if !configured.Null {
+			continue
+		}
+		derived := accountRoleARN(
+			awsPartition(region.Value), accountID.Value, path.Value, prefix.Value, role,
+		)
+		diags = response.Plan.SetAttribute(ctx, role.path, types.String{
+			Value: derived,
+		})
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Account roles", func() {
+	It("Derives the ARNs with the default path", func() {
+		Expect(accountRoleARN("aws", "123456789012", "", "ManagedOpenShift", accountRoles[0])).To(
+			Equal("arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"),
+		)
+		Expect(accountRoleARN("aws", "123456789012", "", "ManagedOpenShift", accountRoles[3])).To(
+			Equal("arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"),
+		)
+	})
+
+	It("Derives the ARNs with a custom path and partition", func() {
+		Expect(accountRoleARN("aws-us-gov", "123456789012", "/rosa/", "my", accountRoles[2])).To(
+			Equal("arn:aws-us-gov:iam::123456789012:role/rosa/my-ControlPlane-Role"),
+		)
+	})
+
+	It("Limits the prefix so that the longest role name fits", func() {
+		Expect(maxAccountRolePrefixLength()).To(Equal(64 - len("-ControlPlane-Role")))
+	})
+
+	It("Derives the policy names and identifiers", func() {
+		Expect(accountRolePolicyName("ManagedOpenShift", accountRoles[1])).To(
+			Equal("ManagedOpenShift-Support-Role-Policy"),
+		)
+		Expect(accountRoleTrustPolicyID(accountRoles[2])).To(
+			Equal("sts_instance_controlplane_trust_policy"),
+		)
+		Expect(accountRolePermissionPolicyID(accountRoles[3])).To(
+			Equal("sts_instance_worker_permission_policy"),
+		)
+	})
+})
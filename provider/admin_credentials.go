@@ -0,0 +1,324 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+const (
+	// defaultAdminUsername is the name of the admin user when the configuration doesn't
+	// contain one.
+	defaultAdminUsername = "cluster-admin"
+
+	// adminIdentityProviderName is the name of the identity provider that the server creates
+	// together with the cluster to contain the admin user.
+	adminIdentityProviderName = "cluster-admin"
+
+	// adminPasswordMinLength is the minimum length of the passwords of admin users, both
+	// the ones provided by the user and the generated ones.
+	adminPasswordMinLength = 14
+
+	// generatedAdminPasswordLength is the length of the generated passwords.
+	generatedAdminPasswordLength = 23
+)
+
+// Characters used to generate passwords. Symbols aren't used so that the generated passwords
+// can be safely used in command lines without quoting.
+const (
+	adminPasswordUpper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	adminPasswordLower  = "abcdefghijklmnopqrstuvwxyz"
+	adminPasswordDigits = "0123456789"
+)
+
+// AdminCredentials contains the user name and password of the admin user that is created
+// together with the cluster.
+type AdminCredentials struct {
+	Username types.String `tfsdk:"username"`
+	Password types.String `tfsdk:"password"`
+}
+
+// adminCredentialsAttribute returns the schema of the 'admin_credentials' attribute shared by
+// the cluster resources. The values are only used when the cluster is created, so each resource
+// passes the plan modifier that it uses for the rest of its immutable attributes.
+func adminCredentialsAttribute(modifier tfsdk.AttributePlanModifier) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Description: "Admin user credentials. When set, an 'htpasswd' identity provider " +
+			"named 'cluster-admin' is created together with the cluster, containing " +
+			"this user as a member of the 'cluster-admins' group. " +
+			"The credentials are only used when the cluster is created. After importing a " +
+			"cluster that has that user the 'username' and 'password' must be explicitly set.",
+		Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+			"username": {
+				Description: fmt.Sprintf(
+					"Admin user name. Default value is '%s'.",
+					defaultAdminUsername,
+				),
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.UseStateForUnknown(),
+					adminCredentialsModifier{modifier},
+				},
+			},
+			"password": {
+				Description: fmt.Sprintf(
+					"Admin user password. It must contain at least %d ASCII characters, "+
+						"no white space, at least one uppercase letter, one lowercase "+
+						"letter and one digit or symbol. A random password is "+
+						"generated if not set.",
+					adminPasswordMinLength,
+				),
+				Type:      types.StringType,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.UseStateForUnknown(),
+					adminCredentialsModifier{modifier},
+				},
+			},
+		}),
+		Optional: true,
+	}
+}
+
+// fillAdminCredentials sets the default user name and generates a password if they haven't
+// been provided by the user.
+func fillAdminCredentials(credentials *AdminCredentials) error {
+	if credentials == nil {
+		return nil
+	}
+	if !isStringSet(credentials.Username) {
+		credentials.Username = types.String{Value: defaultAdminUsername}
+	}
+	if !isStringSet(credentials.Password) {
+		password, err := generateAdminPassword()
+		if err != nil {
+			return err
+		}
+		credentials.Password = types.String{Value: password}
+	}
+	return nil
+}
+
+// generateAdminPassword generates a random password that satisfies the requirements checked by
+// the validation of the admin credentials.
+func generateAdminPassword() (result string, err error) {
+	all := adminPasswordUpper + adminPasswordLower + adminPasswordDigits
+	chars := make([]byte, generatedAdminPasswordLength)
+
+	// Make sure that there is at least one character of each kind, then fill the rest:
+	sets := []string{adminPasswordUpper, adminPasswordLower, adminPasswordDigits}
+	for i := range chars {
+		set := all
+		if i < len(sets) {
+			set = sets[i]
+		}
+		chars[i], err = randomChar(set)
+		if err != nil {
+			return
+		}
+	}
+
+	// Shuffle so that the position of the mandatory characters isn't predictable:
+	for i := len(chars) - 1; i > 0; i-- {
+		var j *big.Int
+		j, err = rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return
+		}
+		chars[i], chars[j.Int64()] = chars[j.Int64()], chars[i]
+	}
+
+	result = string(chars)
+	return
+}
+
+// randomChar returns a random character of the given set.
+func randomChar(set string) (result byte, err error) {
+	index, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return
+	}
+	result = set[index.Int64()]
+	return
+}
+
+// adminCredentialsBuilder returns the builder of the 'htpasswd' identity provider that the
+// server creates together with the cluster, or nil if there are no admin credentials.
+func adminCredentialsBuilder(credentials *AdminCredentials) *cmv1.HTPasswdIdentityProviderBuilder {
+	if credentials == nil {
+		return nil
+	}
+	return cmv1.NewHTPasswdIdentityProvider().Users(
+		cmv1.NewHTPasswdUserList().Items(
+			cmv1.NewHTPasswdUser().
+				Username(credentials.Username.Value).
+				Password(credentials.Password.Value),
+		),
+	)
+}
+
+// adminCredentialsModifier applies the given plan modifier only when the state contains the
+// admin credentials. The state of an imported cluster doesn't contain them, and that case is
+// checked by modifyPlanAdminCredentials instead.
+type adminCredentialsModifier struct {
+	modifier tfsdk.AttributePlanModifier
+}
+
+func (m adminCredentialsModifier) Description(ctx context.Context) string {
+	return m.modifier.Description(ctx)
+}
+
+func (m adminCredentialsModifier) MarkdownDescription(ctx context.Context) string {
+	return m.modifier.MarkdownDescription(ctx)
+}
+
+func (m adminCredentialsModifier) Modify(ctx context.Context, request tfsdk.ModifyAttributePlanRequest,
+	response *tfsdk.ModifyAttributePlanResponse) {
+	if request.AttributeState == nil {
+		return
+	}
+	stateRaw, err := request.AttributeState.ToTerraformValue(ctx)
+	if err != nil || stateRaw == nil {
+		return
+	}
+	m.modifier.Modify(ctx, request, response)
+}
+
+// modifyPlanAdminCredentials rejects plans that add or remove the admin credentials of an
+// existing cluster, as they are only used when the cluster is created. Changes to the values
+// inside the block are rejected by the plan modifiers of the attributes.
+//
+// The state of an imported cluster doesn't contain the credentials, as the server doesn't return
+// them. In that case the credentials of the configuration are accepted if the cluster has the
+// admin user that the server creates for them. The password can't be read, so both the user
+// name and the password must be explicitly set.
+func modifyPlanAdminCredentials(ctx context.Context, collection *cmv1.ClustersClient,
+	request tfsdk.ModifyResourcePlanRequest, response *tfsdk.ModifyResourcePlanResponse) {
+	if request.State.Raw.IsNull() || request.Plan.Raw.IsNull() {
+		return
+	}
+	path := tftypes.NewAttributePath().WithAttributeName("admin_credentials")
+	var state, plan *AdminCredentials
+	diags := request.State.GetAttribute(ctx, path, &state)
+	response.Diagnostics.Append(diags...)
+	diags = request.Plan.GetAttribute(ctx, path, &plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	if state == nil && plan == nil || state != nil && plan != nil {
+		return
+	}
+	if plan == nil {
+		response.Diagnostics.AddAttributeError(
+			path,
+			"Value cannot be changed",
+			"Attribute 'admin_credentials' is only used when the cluster is created, "+
+				"it can't be removed later",
+		)
+		return
+	}
+	if !isStringSet(plan.Username) || !isStringSet(plan.Password) {
+		response.Diagnostics.AddAttributeError(
+			path,
+			"Value cannot be changed",
+			"Attribute 'admin_credentials' is only used when the cluster is created, "+
+				"it can't be added later. If the cluster has been imported the "+
+				"'username' and 'password' attributes must be explicitly set, as they "+
+				"can't be read from the server",
+		)
+		return
+	}
+	var clusterID types.String
+	diags = request.State.GetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("id"),
+		&clusterID)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	found, err := hasAdminUser(ctx, collection.Cluster(clusterID.Value), plan.Username.Value)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			path,
+			"Can't check admin credentials",
+			fmt.Sprintf(
+				"Can't check admin user of cluster '%s': %v",
+				clusterID.Value, err,
+			),
+		)
+		return
+	}
+	if !found {
+		response.Diagnostics.AddAttributeError(
+			path,
+			"Value cannot be changed",
+			fmt.Sprintf(
+				"Attribute 'admin_credentials' is only used when the cluster is created, "+
+					"it can't be added later, and cluster '%s' doesn't have admin user '%s'",
+				clusterID.Value, plan.Username.Value,
+			),
+		)
+	}
+}
+
+// hasAdminUser checks if the cluster has the identity provider that the server creates for the
+// admin credentials, and if it contains the given user.
+func hasAdminUser(ctx context.Context, resource *cmv1.ClusterClient, username string) (bool, error) {
+	listResponse, err := resource.IdentityProviders().List().SendContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	var provider *cmv1.IdentityProvider
+	listResponse.Items().Each(func(item *cmv1.IdentityProvider) bool {
+		if item.Type() == cmv1.IdentityProviderTypeHtpasswd &&
+			strings.EqualFold(item.Name(), adminIdentityProviderName) {
+			provider = item
+			return false
+		}
+		return true
+	})
+	if provider == nil {
+		return false, nil
+	}
+	usersResponse, err := resource.IdentityProviders().IdentityProvider(provider.ID()).
+		HtpasswdUsers().List().SendContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	usersResponse.Items().Each(func(item *cmv1.HTPasswdUser) bool {
+		if item.Username() == username {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found, nil
+}
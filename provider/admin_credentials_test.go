@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Admin credentials validation", func() {
+	// newConfig returns a configuration with valid admin credentials:
+	newConfig := func() *ClusterAdminCredentialsConfig {
+		return &ClusterAdminCredentialsConfig{
+			AdminCredentials: &AdminCredentials{
+				Username: types.String{Value: "admin"},
+				Password: types.String{Value: "Ch4ngeMe-Please"},
+			},
+		}
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	// credentialsPath returns the path of the given attribute of the admin credentials:
+	credentialsPath := func(name string) *tftypes.AttributePath {
+		return tftypes.NewAttributePath().WithAttributeName("admin_credentials").
+			WithAttributeName(name)
+	}
+
+	It("Accepts valid credentials", func() {
+		Expect(validateClusterAdminCredentials(newConfig())).To(BeEmpty())
+	})
+
+	It("Accepts missing credentials and values", func() {
+		Expect(validateClusterAdminCredentials(&ClusterAdminCredentialsConfig{})).To(BeEmpty())
+		config := newConfig()
+		config.AdminCredentials.Username = types.String{Null: true}
+		config.AdminCredentials.Password = types.String{Unknown: true}
+		Expect(validateClusterAdminCredentials(config)).To(BeEmpty())
+	})
+
+	It("Rejects invalid user names", func() {
+		config := newConfig()
+		for _, username := range []string{"", "my admin", "my:admin", "my/admin", "my%admin"} {
+			config.AdminCredentials.Username = types.String{Value: username}
+			Expect(errorPaths(validateClusterAdminCredentials(config))).To(ConsistOf(
+				credentialsPath("username"),
+			))
+		}
+	})
+
+	It("Checks the strength of the password", func() {
+		config := newConfig()
+		for _, password := range []string{"Sh0rt-Password", "ChangeMe-Please", "Ch4ngeMePlease"} {
+			config.AdminCredentials.Password = types.String{Value: password}
+			Expect(validateClusterAdminCredentials(config)).To(BeEmpty())
+		}
+		for _, password := range []string{
+			"Sh0rt-Pass",
+			"Ch4ngeMe Please",
+			"Ch4ngeMe-Pléase",
+			"ch4ngeme-please",
+			"CH4NGEME-PLEASE",
+			"ChangeMePlease",
+		} {
+			config.AdminCredentials.Password = types.String{Value: password}
+			Expect(errorPaths(validateClusterAdminCredentials(config))).To(ConsistOf(
+				credentialsPath("password"),
+			))
+		}
+	})
+})
+
+var _ = Describe("Admin credentials defaults", func() {
+	It("Keeps the values provided by the user", func() {
+		credentials := &AdminCredentials{
+			Username: types.String{Value: "admin"},
+			Password: types.String{Value: "Ch4ngeMe-Please"},
+		}
+		Expect(fillAdminCredentials(credentials)).To(Succeed())
+		Expect(credentials.Username.Value).To(Equal("admin"))
+		Expect(credentials.Password.Value).To(Equal("Ch4ngeMe-Please"))
+	})
+
+	It("Sets the default user name and generates a valid password", func() {
+		credentials := &AdminCredentials{
+			Username: types.String{Unknown: true},
+			Password: types.String{Unknown: true},
+		}
+		Expect(fillAdminCredentials(credentials)).To(Succeed())
+		Expect(credentials.Username.Value).To(Equal(defaultAdminUsername))
+		Expect(credentials.Password.Value).To(HaveLen(generatedAdminPasswordLength))
+		Expect(checkAdminPassword(credentials.Password.Value)).To(BeEmpty())
+	})
+
+	It("Generates different passwords", func() {
+		first, err := generateAdminPassword()
+		Expect(err).ToNot(HaveOccurred())
+		second, err := generateAdminPassword()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first).ToNot(Equal(second))
+	})
+})
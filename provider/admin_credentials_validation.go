@@ -0,0 +1,123 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ClusterAdminCredentialsConfig contains the attributes of the cluster resources that are
+// checked by the validation of the admin credentials.
+type ClusterAdminCredentialsConfig struct {
+	AdminCredentials *AdminCredentials
+}
+
+// readClusterAdminCredentialsConfig reads from the configuration the admin credentials. They
+// are nil when the block isn't set or when it isn't known yet.
+func readClusterAdminCredentialsConfig(ctx context.Context,
+	config tfsdk.Config) (result *ClusterAdminCredentialsConfig, diags diag.Diagnostics) {
+	result = &ClusterAdminCredentialsConfig{}
+	var credentials types.Object
+	diags.Append(config.GetAttribute(
+		ctx, tftypes.NewAttributePath().WithAttributeName("admin_credentials"), &credentials,
+	)...)
+	if diags.HasError() || credentials.Null || credentials.Unknown {
+		return
+	}
+	result.AdminCredentials = &AdminCredentials{
+		Username: objectStringAttribute(credentials, "username"),
+		Password: objectStringAttribute(credentials, "password"),
+	}
+	return
+}
+
+// validateClusterAdminCredentials checks that the user name can be used in an 'htpasswd' file
+// and that the password is strong enough. Values that are null or unknown are ignored, as they
+// will be replaced by the defaults.
+func validateClusterAdminCredentials(config *ClusterAdminCredentialsConfig) (diags diag.Diagnostics) {
+	if config.AdminCredentials == nil {
+		return
+	}
+	path := tftypes.NewAttributePath().WithAttributeName("admin_credentials")
+	username := config.AdminCredentials.Username
+	if !username.Null && !username.Unknown {
+		if username.Value == "" || strings.ContainsAny(username.Value, ":/%") ||
+			strings.IndexFunc(username.Value, unicode.IsSpace) != -1 {
+			diags.AddAttributeError(
+				path.WithAttributeName("username"),
+				"Invalid admin user name",
+				fmt.Sprintf(
+					"User name '%s' should not be empty and should not contain "+
+						"white space, '/', ':' or '%%'",
+					username.Value,
+				),
+			)
+		}
+	}
+	password := config.AdminCredentials.Password
+	if !password.Null && !password.Unknown {
+		problem := checkAdminPassword(password.Value)
+		if problem != "" {
+			diags.AddAttributeError(
+				path.WithAttributeName("password"),
+				"Invalid admin password",
+				problem,
+			)
+		}
+	}
+	return
+}
+
+// checkAdminPassword returns a description of the first requirement that the given password
+// doesn't satisfy, or an empty string if it satisfies all of them. The password itself is never
+// part of the result, as it is sensitive.
+func checkAdminPassword(password string) string {
+	if len(password) < adminPasswordMinLength {
+		return fmt.Sprintf(
+			"Password should contain at least %d characters",
+			adminPasswordMinLength,
+		)
+	}
+	var hasUpper, hasLower, hasOther bool
+	for _, char := range password {
+		switch {
+		case char > unicode.MaxASCII:
+			return "Password should contain only ASCII characters"
+		case unicode.IsSpace(char):
+			return "Password should not contain white space"
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		default:
+			hasOther = true
+		}
+	}
+	if !hasUpper || !hasLower || !hasOther {
+		return "Password should contain at least one uppercase letter, one lowercase " +
+			"letter and one digit or symbol"
+	}
+	return ""
+}
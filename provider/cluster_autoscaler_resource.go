@@ -0,0 +1,528 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type ClusterAutoscalerResourceType struct {
+	logger logging.Logger
+}
+
+type ClusterAutoscalerResource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+func (t *ClusterAutoscalerResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Cluster autoscaler configuration. It only takes effect for machine " +
+			"pools that have autoscaling enabled. Attributes that aren't set use the " +
+			"default values of the server, and removing an attribute from the " +
+			"configuration replaces the autoscaler so that it goes back to the default.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of the cluster.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"balance_similar_node_groups": {
+				Description: "Automatically identify node groups with the same instance " +
+					"type and the same set of labels and try to keep the respective " +
+					"sizes of those node groups balanced.",
+				Type:     types.BoolType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					requiresReplaceIfRemoved(),
+				},
+			},
+			"skip_nodes_with_local_storage": {
+				Description: "Prevents the autoscaler from removing nodes that have " +
+					"pods with local storage.",
+				Type:     types.BoolType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					requiresReplaceIfRemoved(),
+				},
+			},
+			"max_node_provision_time": {
+				Description: "Maximum time that the autoscaler waits for a node to be " +
+					"provisioned, for example '15m'.",
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					requiresReplaceIfRemoved(),
+				},
+			},
+			"resource_limits": {
+				Description: "Limits of the resources of the cluster.",
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"max_nodes_total": {
+						Description: "Maximum number of nodes in all the node groups, " +
+							"including the control plane and infra nodes.",
+						Type:     types.Int64Type,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							requiresReplaceIfRemoved(),
+						},
+					},
+				}),
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					requiresReplaceIfRemoved(),
+				},
+			},
+			"scale_down": {
+				Description: "Configuration of the scale down operation.",
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"enabled": {
+						Description: "Indicates if the autoscaler should scale down " +
+							"the cluster.",
+						Type:     types.BoolType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							requiresReplaceIfRemoved(),
+						},
+					},
+					"unneeded_time": {
+						Description: "How long a node should be unneeded before it " +
+							"is eligible for scale down, for example '10m'.",
+						Type:     types.StringType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							requiresReplaceIfRemoved(),
+						},
+					},
+					"utilization_threshold": {
+						Description: "Node utilization level, defined as the sum of " +
+							"requested resources divided by capacity, below which a " +
+							"node can be considered for scale down. It is a number " +
+							"between 0 and 1, for example '0.5'.",
+						Type:     types.StringType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							requiresReplaceIfRemoved(),
+						},
+					},
+					"delay_after_add": {
+						Description: "How long after scale up that scale down " +
+							"evaluation resumes, for example '10m'.",
+						Type:     types.StringType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							requiresReplaceIfRemoved(),
+						},
+					},
+					"delay_after_delete": {
+						Description: "How long after node deletion that scale down " +
+							"evaluation resumes, for example '10s'.",
+						Type:     types.StringType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							requiresReplaceIfRemoved(),
+						},
+					},
+					"delay_after_failure": {
+						Description: "How long after scale down failure that scale " +
+							"down evaluation resumes, for example '3m'.",
+						Type:     types.StringType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							requiresReplaceIfRemoved(),
+						},
+					},
+				}),
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					requiresReplaceIfRemoved(),
+				},
+			},
+		},
+	}
+	return
+}
+
+func (t *ClusterAutoscalerResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the resource:
+	result = &ClusterAutoscalerResource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+
+	return
+}
+
+func (r *ClusterAutoscalerResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &ClusterAutoscalerState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Wait till the cluster is ready:
+	resource := r.collection.Cluster(state.Cluster.Value)
+	pollCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+	_, err := resource.Poll().
+		Interval(30 * time.Second).
+		Predicate(func(get *cmv1.ClusterGetResponse) bool {
+			return get.Body().State() == cmv1.ClusterStateReady
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't poll cluster state",
+			fmt.Sprintf(
+				"Can't poll state of cluster with identifier '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Create the autoscaler:
+	object, err := r.autoscalerBuilder(state).Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build cluster autoscaler",
+			fmt.Sprintf(
+				"Can't build autoscaler for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	add, err := resource.Autoscaler().Post().Request(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create cluster autoscaler",
+			fmt.Sprintf(
+				"Can't create autoscaler for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object = add.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterAutoscalerResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &ClusterAutoscalerState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Find the autoscaler:
+	get, err := r.collection.Cluster(state.Cluster.Value).Autoscaler().Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster autoscaler",
+			fmt.Sprintf(
+				"Can't find autoscaler for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterAutoscalerResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &ClusterAutoscalerState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Get the plan:
+	plan := &ClusterAutoscalerState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Send the values of the plan. Removed values don't get here, as removing them requires
+	// replacing the autoscaler:
+	patch, err := r.autoscalerBuilder(plan).Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build cluster autoscaler patch",
+			fmt.Sprintf(
+				"Can't build autoscaler patch for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	update, err := r.collection.Cluster(state.Cluster.Value).Autoscaler().Update().
+		Body(patch).
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update cluster autoscaler",
+			fmt.Sprintf(
+				"Can't update autoscaler for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := update.Body()
+
+	// Save the state:
+	r.populateState(object, plan)
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterAutoscalerResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &ClusterAutoscalerState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Send the request to delete the autoscaler:
+	_, err := r.collection.Cluster(state.Cluster.Value).Autoscaler().Delete().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't delete cluster autoscaler",
+			fmt.Sprintf(
+				"Can't delete autoscaler for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *ClusterAutoscalerResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	// The import identifier is the identifier of the cluster, as there is only one autoscaler
+	// per cluster:
+	clusterID := request.ID
+	_, err := r.collection.Cluster(clusterID).Autoscaler().Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster autoscaler",
+			fmt.Sprintf(
+				"Can't find autoscaler for cluster '%s': %v",
+				clusterID, err,
+			),
+		)
+		return
+	}
+
+	// Save only the cluster. The rest of the attributes stay null, like when they aren't in the
+	// configuration, so that the first plan only updates the ones that the configuration sets
+	// instead of replacing the autoscaler:
+	state := &ClusterAutoscalerState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		BalanceSimilarNodeGroups:  types.Bool{Null: true},
+		SkipNodesWithLocalStorage: types.Bool{Null: true},
+		MaxNodeProvisionTime:      types.String{Null: true},
+	}
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterAutoscalerResource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateResourceConfigRequest, response *tfsdk.ValidateResourceConfigResponse) {
+	config := &ClusterAutoscalerState{}
+	diags := request.Config.Get(ctx, config)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	response.Diagnostics.Append(validateClusterAutoscaler(config)...)
+}
+
+// autoscalerBuilder returns a builder containing the values of the given state that are known.
+func (r *ClusterAutoscalerResource) autoscalerBuilder(
+	state *ClusterAutoscalerState) *cmv1.ClusterAutoscalerBuilder {
+	builder := cmv1.NewClusterAutoscaler()
+	if !state.BalanceSimilarNodeGroups.Unknown && !state.BalanceSimilarNodeGroups.Null {
+		builder.BalanceSimilarNodeGroups(state.BalanceSimilarNodeGroups.Value)
+	}
+	if !state.SkipNodesWithLocalStorage.Unknown && !state.SkipNodesWithLocalStorage.Null {
+		builder.SkipNodesWithLocalStorage(state.SkipNodesWithLocalStorage.Value)
+	}
+	if isStringSet(state.MaxNodeProvisionTime) {
+		builder.MaxNodeProvisionTime(state.MaxNodeProvisionTime.Value)
+	}
+	if state.ResourceLimits != nil {
+		limits := cmv1.NewAutoscalerResourceLimits()
+		maxNodesTotal := state.ResourceLimits.MaxNodesTotal
+		if !maxNodesTotal.Unknown && !maxNodesTotal.Null {
+			limits.MaxNodesTotal(int(maxNodesTotal.Value))
+		}
+		if !limits.Empty() {
+			builder.ResourceLimits(limits)
+		}
+	}
+	if state.ScaleDown != nil {
+		scaleDown := cmv1.NewAutoscalerScaleDownConfig()
+		enabled := state.ScaleDown.Enabled
+		if !enabled.Unknown && !enabled.Null {
+			scaleDown.Enabled(enabled.Value)
+		}
+		if isStringSet(state.ScaleDown.UnneededTime) {
+			scaleDown.UnneededTime(state.ScaleDown.UnneededTime.Value)
+		}
+		if isStringSet(state.ScaleDown.UtilizationThreshold) {
+			scaleDown.UtilizationThreshold(state.ScaleDown.UtilizationThreshold.Value)
+		}
+		if isStringSet(state.ScaleDown.DelayAfterAdd) {
+			scaleDown.DelayAfterAdd(state.ScaleDown.DelayAfterAdd.Value)
+		}
+		if isStringSet(state.ScaleDown.DelayAfterDelete) {
+			scaleDown.DelayAfterDelete(state.ScaleDown.DelayAfterDelete.Value)
+		}
+		if isStringSet(state.ScaleDown.DelayAfterFailure) {
+			scaleDown.DelayAfterFailure(state.ScaleDown.DelayAfterFailure.Value)
+		}
+		if !scaleDown.Empty() {
+			builder.ScaleDown(scaleDown)
+		}
+	}
+	return builder
+}
+
+// populateState copies the data from the API object to the Terraform state. Only the attributes
+// that aren't null in the state are populated, as the rest aren't in the configuration and the
+// values returned by the server for them are the defaults.
+func (r *ClusterAutoscalerResource) populateState(object *cmv1.ClusterAutoscaler,
+	state *ClusterAutoscalerState) {
+	if !state.BalanceSimilarNodeGroups.Null {
+		state.BalanceSimilarNodeGroups = optionalBool(object.GetBalanceSimilarNodeGroups())
+	}
+	if !state.SkipNodesWithLocalStorage.Null {
+		state.SkipNodesWithLocalStorage = optionalBool(object.GetSkipNodesWithLocalStorage())
+	}
+	if !state.MaxNodeProvisionTime.Null {
+		state.MaxNodeProvisionTime = optionalString(object.GetMaxNodeProvisionTime())
+	}
+	if state.ResourceLimits != nil && !state.ResourceLimits.MaxNodesTotal.Null {
+		maxNodesTotal, ok := object.ResourceLimits().GetMaxNodesTotal()
+		state.ResourceLimits.MaxNodesTotal = types.Int64{
+			Value: int64(maxNodesTotal),
+			Null:  !ok,
+		}
+	}
+	if state.ScaleDown != nil {
+		scaleDown := object.ScaleDown()
+		if !state.ScaleDown.Enabled.Null {
+			state.ScaleDown.Enabled = optionalBool(scaleDown.GetEnabled())
+		}
+		if !state.ScaleDown.UnneededTime.Null {
+			state.ScaleDown.UnneededTime = optionalString(scaleDown.GetUnneededTime())
+		}
+		if !state.ScaleDown.UtilizationThreshold.Null {
+			state.ScaleDown.UtilizationThreshold = optionalString(
+				scaleDown.GetUtilizationThreshold(),
+			)
+		}
+		if !state.ScaleDown.DelayAfterAdd.Null {
+			state.ScaleDown.DelayAfterAdd = optionalString(scaleDown.GetDelayAfterAdd())
+		}
+		if !state.ScaleDown.DelayAfterDelete.Null {
+			state.ScaleDown.DelayAfterDelete = optionalString(scaleDown.GetDelayAfterDelete())
+		}
+		if !state.ScaleDown.DelayAfterFailure.Null {
+			state.ScaleDown.DelayAfterFailure = optionalString(scaleDown.GetDelayAfterFailure())
+		}
+	}
+}
+
+// requiresReplaceIfRemoved returns a plan modifier that replaces the autoscaler when the
+// attribute is removed from the configuration. The server keeps the values that aren't sent in
+// updates, so replacing it is the only way to go back to the default value.
+func requiresReplaceIfRemoved() tfsdk.AttributePlanModifier {
+	return tfsdk.RequiresReplaceIf(
+		func(ctx context.Context, state, config attr.Value,
+			path *tftypes.AttributePath) (result bool, diags diag.Diagnostics) {
+			stateRaw, err := state.ToTerraformValue(ctx)
+			if err != nil {
+				diags.AddAttributeError(path, "Can't convert state value", err.Error())
+				return
+			}
+			configRaw, err := config.ToTerraformValue(ctx)
+			if err != nil {
+				diags.AddAttributeError(path, "Can't convert config value", err.Error())
+				return
+			}
+			result = stateRaw != nil && configRaw == nil
+			return
+		},
+		"Replaces the autoscaler when the value is removed from the configuration.",
+		"Replaces the autoscaler when the value is removed from the configuration.",
+	)
+}
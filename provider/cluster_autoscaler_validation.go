@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// validateClusterAutoscaler checks that the durations used by the cluster autoscaler are valid,
+// that the utilization threshold is a number between zero and one and that the maximum number
+// of nodes is positive. Values that are unknown or null are ignored.
+func validateClusterAutoscaler(config *ClusterAutoscalerState) (diags diag.Diagnostics) {
+	checkDuration(tftypes.NewAttributePath().WithAttributeName("max_node_provision_time"),
+		config.MaxNodeProvisionTime, &diags)
+	if config.ResourceLimits != nil {
+		total := config.ResourceLimits.MaxNodesTotal
+		if !total.Unknown && !total.Null && total.Value < 1 {
+			diags.AddAttributeError(
+				tftypes.NewAttributePath().WithAttributeName("resource_limits").
+					WithAttributeName("max_nodes_total"),
+				"Invalid maximum number of nodes",
+				fmt.Sprintf(
+					"Value of 'max_nodes_total' should be at least 1, but it is %d",
+					total.Value,
+				),
+			)
+		}
+	}
+	if config.ScaleDown != nil {
+		path := tftypes.NewAttributePath().WithAttributeName("scale_down")
+		checkDuration(path.WithAttributeName("unneeded_time"),
+			config.ScaleDown.UnneededTime, &diags)
+		checkDuration(path.WithAttributeName("delay_after_add"),
+			config.ScaleDown.DelayAfterAdd, &diags)
+		checkDuration(path.WithAttributeName("delay_after_delete"),
+			config.ScaleDown.DelayAfterDelete, &diags)
+		checkDuration(path.WithAttributeName("delay_after_failure"),
+			config.ScaleDown.DelayAfterFailure, &diags)
+		checkUtilizationThreshold(path.WithAttributeName("utilization_threshold"),
+			config.ScaleDown.UtilizationThreshold, &diags)
+	}
+	return
+}
+
+// checkDuration checks that the given value is a duration that isn't negative, using the
+// format of the Go 'time.ParseDuration' function, for example '10m' or '1h30m', which is the
+// format expected by the cluster autoscaler.
+func checkDuration(path *tftypes.AttributePath, value types.String, diags *diag.Diagnostics) {
+	if value.Unknown || value.Null {
+		return
+	}
+	duration, err := time.ParseDuration(value.Value)
+	if err != nil || duration < 0 {
+		diags.AddAttributeError(
+			path,
+			"Invalid duration",
+			fmt.Sprintf(
+				"Value '%s' isn't a valid duration, it should be a sequence of numbers "+
+					"with units, for example '10m' or '1h30m'",
+				value.Value,
+			),
+		)
+	}
+}
+
+// checkUtilizationThreshold checks that the given value is a number between zero and one.
+func checkUtilizationThreshold(path *tftypes.AttributePath, value types.String,
+	diags *diag.Diagnostics) {
+	if value.Unknown || value.Null {
+		return
+	}
+	threshold, err := strconv.ParseFloat(value.Value, 64)
+	if err != nil || !(threshold >= 0 && threshold <= 1) {
+		diags.AddAttributeError(
+			path,
+			"Invalid utilization threshold",
+			fmt.Sprintf(
+				"Value '%s' isn't a valid utilization threshold, it should be a number "+
+					"between 0 and 1",
+				value.Value,
+			),
+		)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Cluster autoscaler validation", func() {
+	// newConfig returns a valid configuration:
+	newConfig := func() *ClusterAutoscalerState {
+		return &ClusterAutoscalerState{
+			MaxNodeProvisionTime: types.String{Value: "15m"},
+			ResourceLimits: &AutoscalerResourceLimits{
+				MaxNodesTotal: types.Int64{Value: 100},
+			},
+			ScaleDown: &AutoscalerScaleDownConfig{
+				Enabled:              types.Bool{Value: true},
+				UnneededTime:         types.String{Value: "10m"},
+				UtilizationThreshold: types.String{Value: "0.5"},
+				DelayAfterAdd:        types.String{Value: "1h30m"},
+				DelayAfterDelete:     types.String{Value: "10s"},
+				DelayAfterFailure:    types.String{Null: true},
+			},
+		}
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	// scaleDownPath returns the path of the given attribute of the scale down configuration:
+	scaleDownPath := func(name string) *tftypes.AttributePath {
+		return tftypes.NewAttributePath().WithAttributeName("scale_down").WithAttributeName(name)
+	}
+
+	It("Accepts a valid configuration", func() {
+		Expect(validateClusterAutoscaler(newConfig())).To(BeEmpty())
+	})
+
+	It("Accepts missing blocks and unknown values", func() {
+		Expect(validateClusterAutoscaler(&ClusterAutoscalerState{
+			MaxNodeProvisionTime: types.String{Unknown: true},
+		})).To(BeEmpty())
+		config := newConfig()
+		config.ResourceLimits.MaxNodesTotal = types.Int64{Unknown: true}
+		config.ScaleDown.UnneededTime = types.String{Unknown: true}
+		config.ScaleDown.UtilizationThreshold = types.String{Unknown: true}
+		Expect(validateClusterAutoscaler(config)).To(BeEmpty())
+	})
+
+	It("Rejects invalid durations", func() {
+		config := newConfig()
+		config.MaxNodeProvisionTime = types.String{Value: "15"}
+		config.ScaleDown.UnneededTime = types.String{Value: "ten minutes"}
+		config.ScaleDown.DelayAfterAdd = types.String{Value: "-10m"}
+		config.ScaleDown.DelayAfterDelete = types.String{Value: ""}
+		config.ScaleDown.DelayAfterFailure = types.String{Value: "3d"}
+		Expect(errorPaths(validateClusterAutoscaler(config))).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("max_node_provision_time"),
+			scaleDownPath("unneeded_time"),
+			scaleDownPath("delay_after_add"),
+			scaleDownPath("delay_after_delete"),
+			scaleDownPath("delay_after_failure"),
+		))
+	})
+
+	It("Checks the range of the utilization threshold", func() {
+		config := newConfig()
+		for _, threshold := range []string{"0", "0.05", "1"} {
+			config.ScaleDown.UtilizationThreshold = types.String{Value: threshold}
+			Expect(validateClusterAutoscaler(config)).To(BeEmpty())
+		}
+		for _, threshold := range []string{"", "half", "-0.1", "1.5", "NaN"} {
+			config.ScaleDown.UtilizationThreshold = types.String{Value: threshold}
+			Expect(errorPaths(validateClusterAutoscaler(config))).To(ConsistOf(
+				scaleDownPath("utilization_threshold"),
+			))
+		}
+	})
+
+	It("Rejects a maximum number of nodes that isn't positive", func() {
+		config := newConfig()
+		config.ResourceLimits.MaxNodesTotal = types.Int64{Value: 0}
+		Expect(errorPaths(validateClusterAutoscaler(config))).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("resource_limits").
+				WithAttributeName("max_nodes_total"),
+		))
+	})
+})
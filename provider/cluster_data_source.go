@@ -0,0 +1,168 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type ClusterDataSourceType struct {
+}
+
+type ClusterDataSource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+// clusterResourceOnlyAttributes are the attributes of the cluster resource that are either
+// secrets that the API doesn't return or that only control the behaviour of the resource, so
+// they aren't part of the data source.
+var clusterResourceOnlyAttributes = []string{
+	"admin_credentials",
+	"aws_access_key_id",
+	"aws_secret_access_key",
+	"aws_subnet_cidr_blocks",
+	"deletion_protection",
+	"wait",
+}
+
+func (t *ClusterDataSourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	resourceSchema, diags := (&ClusterResourceType{}).GetSchema(ctx)
+	if diags.HasError() {
+		return
+	}
+	for _, name := range clusterResourceOnlyAttributes {
+		delete(resourceSchema.Attributes, name)
+	}
+	attributes := computedAttributes(resourceSchema.Attributes)
+	for name, attribute := range clusterSelectorAttributes() {
+		attributes[name] = attribute
+	}
+	result = tfsdk.Schema{
+		Description: "Existing OpenShift managed cluster, selected by identifier, name " +
+			"or external identifier.",
+		Attributes: attributes,
+	}
+	return
+}
+
+func (t *ClusterDataSourceType) NewDataSource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.DataSource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation:
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the data source:
+	result = &ClusterDataSource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+	return
+}
+
+func (s *ClusterDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
+	response *tfsdk.ReadDataSourceResponse) {
+	// Get the configuration:
+	state := &ClusterDataSourceState{}
+	diags := request.Config.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Find the cluster:
+	id, err := findClusterID(ctx, s.collection, ClusterSelector{
+		ID:         state.ID,
+		Name:       state.Name,
+		ExternalID: state.ExternalID,
+	})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf("Can't find cluster: %v", err),
+		)
+		return
+	}
+	get, err := s.collection.Cluster(id).Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf(
+				"Can't find cluster with identifier '%s': %v",
+				id, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Populate the state using the same code than the resource:
+	clusterState := &ClusterState{
+		AWSSubnetIDs: types.List{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+		AvailabilityZones: types.List{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+	}
+	populateClusterState(object, clusterState)
+	state = &ClusterDataSourceState{
+		APIURL:             clusterState.APIURL,
+		AWSAccountID:       clusterState.AWSAccountID,
+		AWSSubnetIDs:       clusterState.AWSSubnetIDs,
+		AWSPrivateLink:     clusterState.AWSPrivateLink,
+		CCSEnabled:         clusterState.CCSEnabled,
+		CloudProvider:      clusterState.CloudProvider,
+		CloudRegion:        clusterState.CloudRegion,
+		ComputeMachineType: clusterState.ComputeMachineType,
+		ComputeNodes:       clusterState.ComputeNodes,
+		ConsoleURL:         clusterState.ConsoleURL,
+		HostPrefix:         clusterState.HostPrefix,
+		ID:                 clusterState.ID,
+		ExternalID: types.String{
+			Value: object.ExternalID(),
+		},
+		Product:           clusterState.Product,
+		MachineCIDR:       clusterState.MachineCIDR,
+		MultiAZ:           clusterState.MultiAZ,
+		AvailabilityZones: clusterState.AvailabilityZones,
+		Name:              clusterState.Name,
+		PodCIDR:           clusterState.PodCIDR,
+		Properties:        clusterState.Properties,
+		ServiceCIDR:       clusterState.ServiceCIDR,
+		Proxy:             clusterState.Proxy,
+		State:             clusterState.State,
+		Version:           clusterState.Version,
+		ChannelGroup:      clusterState.ChannelGroup,
+	}
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,522 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type ClusterIngressResourceType struct {
+	logger logging.Logger
+}
+
+type ClusterIngressResource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+func (t *ClusterIngressResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Ingress controller of a cluster.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of the cluster.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"id": {
+				Description: "Unique identifier of the ingress.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"default": {
+				Description: "Indicates if this is the default ingress of the cluster. " +
+					"The default ingress always exists, so when this is 'true' the " +
+					"existing ingress is adopted instead of creating a new one, and " +
+					"destroying the resource resets its settings instead of deleting it. " +
+					"Default value is 'false'.",
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"dns_name": {
+				Description: "DNS name of the ingress.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"listening": {
+				Description: "Listening method of the ingress, 'external' or 'internal'. " +
+					"Use 'internal' to make the router private.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"route_selectors": {
+				Description: "Labels that the routes must have to be served by the ingress.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
+			"excluded_namespaces": {
+				Description: "Namespaces whose routes aren't served by the ingress.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
+			"route_namespace_ownership_policy": {
+				Description: "Namespace ownership policy of the routes, 'Strict' or " +
+					"'InterNamespaceAllowed'.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"route_wildcard_policy": {
+				Description: "Wildcard policy of the routes, 'WildcardsDisallowed' or " +
+					"'WildcardsAllowed'.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+	return
+}
+
+func (t *ClusterIngressResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the resource:
+	result = &ClusterIngressResource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+
+	return
+}
+
+func (r *ClusterIngressResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &ClusterIngressState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Wait till the cluster is ready:
+	resource := r.collection.Cluster(state.Cluster.Value)
+	pollCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+	_, err := resource.Poll().
+		Interval(30 * time.Second).
+		Predicate(func(get *cmv1.ClusterGetResponse) bool {
+			return get.Body().State() == cmv1.ClusterStateReady
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't poll cluster state",
+			fmt.Sprintf(
+				"Can't poll state of cluster with identifier '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// All the values of the plan are sent, as there is nothing to compare them with:
+	builder := r.ingressPatch(&ClusterIngressState{
+		Listening:                     types.String{Null: true},
+		RouteSelectors:                types.Map{ElemType: types.StringType, Null: true},
+		ExcludedNamespaces:            types.List{ElemType: types.StringType, Null: true},
+		RouteNamespaceOwnershipPolicy: types.String{Null: true},
+		RouteWildcardPolicy:           types.String{Null: true},
+	}, state)
+
+	var object *cmv1.Ingress
+	if !state.Default.Unknown && !state.Default.Null && state.Default.Value {
+		object, err = r.adoptDefaultIngress(ctx, state.Cluster.Value, builder)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't adopt default ingress",
+				fmt.Sprintf(
+					"Can't adopt default ingress of cluster '%s': %v",
+					state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+	} else {
+		object, err = builder.Build()
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't build ingress",
+				fmt.Sprintf(
+					"Can't build ingress for cluster '%s': %v",
+					state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		add, err := resource.Ingresses().Add().Body(object).SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't create ingress",
+				fmt.Sprintf(
+					"Can't create ingress for cluster '%s': %v",
+					state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		object = add.Body()
+	}
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// adoptDefaultIngress finds the default ingress of the cluster and applies to it the given
+// changes, if any.
+func (r *ClusterIngressResource) adoptDefaultIngress(ctx context.Context, clusterID string,
+	builder *cmv1.IngressBuilder) (result *cmv1.Ingress, err error) {
+	collection := r.collection.Cluster(clusterID).Ingresses()
+	list, err := collection.List().SendContext(ctx)
+	if err != nil {
+		return
+	}
+	list.Items().Each(func(ingress *cmv1.Ingress) bool {
+		if ingress.Default() {
+			result = ingress
+			return false
+		}
+		return true
+	})
+	if result == nil {
+		err = fmt.Errorf("the cluster doesn't have a default ingress")
+		return
+	}
+	if builder.Empty() {
+		return
+	}
+	patch, err := builder.Build()
+	if err != nil {
+		return
+	}
+	update, err := collection.Ingress(result.ID()).Update().Body(patch).SendContext(ctx)
+	if err != nil {
+		return
+	}
+	result = update.Body()
+	return
+}
+
+func (r *ClusterIngressResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &ClusterIngressState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Find the ingress:
+	get, err := r.collection.Cluster(state.Cluster.Value).
+		Ingresses().
+		Ingress(state.ID.Value).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find ingress",
+			fmt.Sprintf(
+				"Can't find ingress with identifier '%s' for cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterIngressResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &ClusterIngressState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Get the plan:
+	plan := &ClusterIngressState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Send the changes, or just get the ingress again if there are no changes to send:
+	resource := r.collection.Cluster(state.Cluster.Value).
+		Ingresses().
+		Ingress(state.ID.Value)
+	var object *cmv1.Ingress
+	builder := r.ingressPatch(state, plan)
+	if builder.Empty() {
+		get, err := resource.Get().SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't find ingress",
+				fmt.Sprintf(
+					"Can't find ingress with identifier '%s' for cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		object = get.Body()
+	} else {
+		patch, err := builder.Build()
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't build ingress patch",
+				fmt.Sprintf(
+					"Can't build patch for ingress with identifier '%s' for cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		update, err := resource.Update().Body(patch).SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't update ingress",
+				fmt.Sprintf(
+					"Can't update ingress with identifier '%s' for cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		object = update.Body()
+	}
+
+	// Take the collections from the plan, so that empty ones are preserved:
+	state.RouteSelectors = plan.RouteSelectors
+	state.ExcludedNamespaces = plan.ExcludedNamespaces
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// ingressPatch returns a builder containing the attributes that are different in the plan and
+// in the state. Route selectors and excluded namespaces that have been removed from the plan
+// are sent as empty values, so that the server removes them.
+func (r *ClusterIngressResource) ingressPatch(state, plan *ClusterIngressState) *cmv1.IngressBuilder {
+	builder := cmv1.NewIngress()
+	listening, ok := shouldPatchString(state.Listening, plan.Listening)
+	if ok {
+		builder.Listening(cmv1.ListeningMethod(listening))
+	}
+	if !plan.RouteSelectors.Unknown && !plan.RouteSelectors.Equal(state.RouteSelectors) {
+		builder.RouteSelectors(stringMapValues(plan.RouteSelectors))
+	}
+	if !plan.ExcludedNamespaces.Unknown && !plan.ExcludedNamespaces.Equal(state.ExcludedNamespaces) {
+		builder.ExcludedNamespaces(stringListValues(plan.ExcludedNamespaces)...)
+	}
+	ownershipPolicy, ok := shouldPatchString(state.RouteNamespaceOwnershipPolicy,
+		plan.RouteNamespaceOwnershipPolicy)
+	if ok {
+		builder.RouteNamespaceOwnershipPolicy(cmv1.NamespaceOwnershipPolicy(ownershipPolicy))
+	}
+	wildcardPolicy, ok := shouldPatchString(state.RouteWildcardPolicy, plan.RouteWildcardPolicy)
+	if ok {
+		builder.RouteWildcardPolicy(cmv1.WildcardPolicy(wildcardPolicy))
+	}
+	return builder
+}
+
+func (r *ClusterIngressResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &ClusterIngressState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// The default ingress can't be deleted, so its settings are restored to the values that
+	// it has when the cluster is created:
+	resource := r.collection.Cluster(state.Cluster.Value).
+		Ingresses().
+		Ingress(state.ID.Value)
+	if !state.Default.Null && state.Default.Value {
+		patch, err := cmv1.NewIngress().
+			Listening(cmv1.ListeningMethodExternal).
+			RouteSelectors(map[string]string{}).
+			ExcludedNamespaces().
+			RouteNamespaceOwnershipPolicy(cmv1.NamespaceOwnershipPolicyStrict).
+			RouteWildcardPolicy(cmv1.WildcardPolicyWildcardsDisallowed).
+			Build()
+		if err == nil {
+			_, err = resource.Update().Body(patch).SendContext(ctx)
+		}
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't reset default ingress",
+				fmt.Sprintf(
+					"Can't reset default ingress with identifier '%s' for cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+	} else {
+		_, err := resource.Delete().SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't delete ingress",
+				fmt.Sprintf(
+					"Can't delete ingress with identifier '%s' for cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *ClusterIngressResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	// The import identifier contains the identifiers of the cluster and the ingress:
+	values, err := splitImportID(request.ID, "cluster", "ingress_id")
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid import identifier",
+			fmt.Sprintf("Can't import ingress: %v", err),
+		)
+		return
+	}
+	clusterID, ingressID := values[0], values[1]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).
+		Ingresses().
+		Ingress(ingressID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find ingress",
+			fmt.Sprintf(
+				"Can't find ingress with identifier '%s' for cluster '%s': %v",
+				ingressID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &ClusterIngressState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterIngressResource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateResourceConfigRequest, response *tfsdk.ValidateResourceConfigResponse) {
+	config := &ClusterIngressState{}
+	diags := request.Config.Get(ctx, config)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	response.Diagnostics.Append(validateClusterIngress(config)...)
+}
+
+// populateState copies the data from the API object to the Terraform state.
+func (r *ClusterIngressResource) populateState(object *cmv1.Ingress, state *ClusterIngressState) {
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	state.Default = types.Bool{
+		Value: object.Default(),
+	}
+	state.DNSName = optionalString(object.GetDNSName())
+	listening, ok := object.GetListening()
+	state.Listening = optionalString(string(listening), ok)
+	// The server doesn't distinguish empty and missing route selectors or excluded namespaces,
+	// so empty values in the state are preserved:
+	routeSelectors := object.RouteSelectors()
+	if len(routeSelectors) > 0 || !isEmptyMap(state.RouteSelectors) {
+		state.RouteSelectors = stringMapValue(routeSelectors)
+	}
+	excludedNamespaces := object.ExcludedNamespaces()
+	if len(excludedNamespaces) > 0 || !isEmptyList(state.ExcludedNamespaces) {
+		state.ExcludedNamespaces = stringListValue(excludedNamespaces)
+	}
+	ownershipPolicy, ok := object.GetRouteNamespaceOwnershipPolicy()
+	state.RouteNamespaceOwnershipPolicy = optionalString(string(ownershipPolicy), ok)
+	wildcardPolicy, ok := object.GetRouteWildcardPolicy()
+	state.RouteWildcardPolicy = optionalString(string(wildcardPolicy), ok)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Values accepted by OCM for the enumerated attributes of ingresses.
+var (
+	ingressListeningMethods = []string{
+		string(cmv1.ListeningMethodExternal),
+		string(cmv1.ListeningMethodInternal),
+	}
+	ingressNamespaceOwnershipPolicies = []string{
+		string(cmv1.NamespaceOwnershipPolicyStrict),
+		string(cmv1.NamespaceOwnershipPolicyInterNamespaceAllowed),
+	}
+	ingressWildcardPolicies = []string{
+		string(cmv1.WildcardPolicyWildcardsDisallowed),
+		string(cmv1.WildcardPolicyWildcardsAllowed),
+	}
+)
+
+// validateClusterIngress checks that the enumerated attributes of an ingress have one of the
+// values accepted by OCM and that the route selectors don't contain empty keys. Values that are
+// unknown or null are ignored.
+func validateClusterIngress(config *ClusterIngressState) (diags diag.Diagnostics) {
+	checkEnumValue("listening", config.Listening, ingressListeningMethods, &diags)
+	checkEnumValue("route_namespace_ownership_policy", config.RouteNamespaceOwnershipPolicy,
+		ingressNamespaceOwnershipPolicies, &diags)
+	checkEnumValue("route_wildcard_policy", config.RouteWildcardPolicy,
+		ingressWildcardPolicies, &diags)
+	if !config.RouteSelectors.Unknown && !config.RouteSelectors.Null {
+		for key := range config.RouteSelectors.Elems {
+			if strings.TrimSpace(key) == "" {
+				diags.AddAttributeError(
+					tftypes.NewAttributePath().WithAttributeName("route_selectors"),
+					"Invalid route selector",
+					"Keys of 'route_selectors' should not be empty",
+				)
+				break
+			}
+		}
+	}
+	return
+}
+
+// checkEnumValue checks that the value of the given attribute is one of the allowed values.
+func checkEnumValue(name string, value types.String, allowed []string, diags *diag.Diagnostics) {
+	if value.Unknown || value.Null {
+		return
+	}
+	for _, candidate := range allowed {
+		if value.Value == candidate {
+			return
+		}
+	}
+	diags.AddAttributeError(
+		tftypes.NewAttributePath().WithAttributeName(name),
+		"Invalid value",
+		fmt.Sprintf(
+			"Value of '%s' should be '%s', but it is '%s'",
+			name, strings.Join(allowed, "' or '"), value.Value,
+		),
+	)
+}
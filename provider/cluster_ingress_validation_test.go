@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Ingress validation", func() {
+	// newConfig returns a configuration where all the validated attributes are null:
+	newConfig := func() *ClusterIngressState {
+		return &ClusterIngressState{
+			Listening:                     types.String{Null: true},
+			RouteSelectors:                types.Map{ElemType: types.StringType, Null: true},
+			RouteNamespaceOwnershipPolicy: types.String{Null: true},
+			RouteWildcardPolicy:           types.String{Null: true},
+		}
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	It("Accepts null and unknown values", func() {
+		Expect(validateClusterIngress(newConfig())).To(BeEmpty())
+		config := newConfig()
+		config.Listening = types.String{Unknown: true}
+		config.RouteSelectors = types.Map{ElemType: types.StringType, Unknown: true}
+		config.RouteNamespaceOwnershipPolicy = types.String{Unknown: true}
+		config.RouteWildcardPolicy = types.String{Unknown: true}
+		Expect(validateClusterIngress(config)).To(BeEmpty())
+	})
+
+	It("Accepts the values supported by OCM", func() {
+		config := newConfig()
+		config.Listening = types.String{Value: "internal"}
+		config.RouteSelectors = types.Map{
+			ElemType: types.StringType,
+			Elems: map[string]attr.Value{
+				"route": types.String{Value: "internal"},
+			},
+		}
+		config.RouteNamespaceOwnershipPolicy = types.String{Value: "InterNamespaceAllowed"}
+		config.RouteWildcardPolicy = types.String{Value: "WildcardsAllowed"}
+		Expect(validateClusterIngress(config)).To(BeEmpty())
+	})
+
+	It("Rejects unsupported values", func() {
+		config := newConfig()
+		config.Listening = types.String{Value: "private"}
+		config.RouteNamespaceOwnershipPolicy = types.String{Value: "strict"}
+		config.RouteWildcardPolicy = types.String{Value: "Allowed"}
+		Expect(errorPaths(validateClusterIngress(config))).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("listening"),
+			tftypes.NewAttributePath().WithAttributeName("route_namespace_ownership_policy"),
+			tftypes.NewAttributePath().WithAttributeName("route_wildcard_policy"),
+		))
+	})
+
+	It("Rejects empty route selector keys", func() {
+		config := newConfig()
+		config.RouteSelectors = types.Map{
+			ElemType: types.StringType,
+			Elems: map[string]attr.Value{
+				" ": types.String{Value: "internal"},
+			},
+		}
+		Expect(errorPaths(validateClusterIngress(config))).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("route_selectors"),
+		))
+	})
+})
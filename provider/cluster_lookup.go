@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// clusterImportNamePrefix is the prefix of the import identifiers that select the cluster by name,
+// for example 'name:my-cluster'.
+const clusterImportNamePrefix = "name:"
+
+// externalIDRE is the regular expression used to check if an import identifier is an external
+// cluster identifier, which is an UUID, instead of an internal one.
+var externalIDRE = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
+)
+
+// ClusterSelector contains the attributes that the cluster data sources use to find the cluster.
+// Exactly one of them should be set.
+type ClusterSelector struct {
+	ID         types.String
+	Name       types.String
+	ExternalID types.String
+}
+
+// findClusterID returns the identifier of the cluster selected by the given attributes. When the
+// cluster is selected by name or external identifier it searches the clusters and fails if there
+// isn't exactly one match.
+func findClusterID(ctx context.Context, collection *cmv1.ClustersClient,
+	selector ClusterSelector) (string, error) {
+	var field string
+	var value types.String
+	count := 0
+	if !selector.ID.Unknown && !selector.ID.Null {
+		count++
+	}
+	if !selector.Name.Unknown && !selector.Name.Null {
+		field = "name"
+		value = selector.Name
+		count++
+	}
+	if !selector.ExternalID.Unknown && !selector.ExternalID.Null {
+		field = "external_id"
+		value = selector.ExternalID
+		count++
+	}
+	if count != 1 {
+		return "", fmt.Errorf("exactly one of 'id', 'name' or 'external_id' should be set")
+	}
+	if field == "" {
+		return selector.ID.Value, nil
+	}
+	return searchClusterID(ctx, collection, field, value.Value)
+}
+
+// searchClusterID returns the identifier of the only cluster that has the given value in the
+// given field.
+func searchClusterID(ctx context.Context, collection *cmv1.ClustersClient, field string,
+	value string) (string, error) {
+	search := fmt.Sprintf("%s = '%s'", field, strings.ReplaceAll(value, "'", "''"))
+	listResponse, err := collection.List().Search(search).Size(10).SendContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("can't search clusters with %s '%s': %v", field, value, err)
+	}
+	switch {
+	case listResponse.Total() == 0:
+		return "", fmt.Errorf("there is no cluster with %s '%s'", field, value)
+	case listResponse.Total() > 1:
+		ids := make([]string, 0, listResponse.Size())
+		listResponse.Items().Each(func(item *cmv1.Cluster) bool {
+			ids = append(ids, fmt.Sprintf("'%s'", item.ID()))
+			return true
+		})
+		return "", fmt.Errorf(
+			"there are %d clusters with %s '%s', including %s, use the identifier "+
+				"to select one of them",
+			listResponse.Total(), field, value, strings.Join(ids, ", "),
+		)
+	}
+	return listResponse.Items().Get(0).ID(), nil
+}
+
+// importClusterID returns the identifier of the cluster selected by the identifier used in an
+// import command. It can be the internal identifier, the external identifier or the name of the
+// cluster with the 'name:' prefix.
+func importClusterID(ctx context.Context, collection *cmv1.ClustersClient,
+	importID string) (string, error) {
+	switch {
+	case strings.HasPrefix(importID, clusterImportNamePrefix):
+		name := strings.TrimPrefix(importID, clusterImportNamePrefix)
+		if name == "" {
+			return "", fmt.Errorf("the cluster name after the '%s' prefix is empty", clusterImportNamePrefix)
+		}
+		return searchClusterID(ctx, collection, "name", name)
+	case externalIDRE.MatchString(importID):
+		return searchClusterID(ctx, collection, "external_id", importID)
+	default:
+		return importID, nil
+	}
+}
@@ -26,17 +26,25 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/errors"
 	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
+// deletionProtectionFormat is the error that the cluster resources return when they refuse to
+// delete a cluster that has the deletion protection enabled.
+const deletionProtectionFormat = "Can't delete cluster with identifier '%s', deletion protection is enabled. " +
+	"Set 'deletion_protection' to false and apply the change before destroying the cluster"
+
 type ClusterResourceType struct {
+	logger logging.Logger
 }
 
 type ClusterResource struct {
 	logger     logging.Logger
 	collection *cmv1.ClustersClient
+	versions   *cmv1.VersionsClient
 }
 
 func (t *ClusterResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
@@ -137,6 +145,15 @@ func (t *ClusterResourceType) GetSchema(ctx context.Context) (result tfsdk.Schem
 				Optional:    true,
 				Sensitive:   true,
 			},
+			"aws_subnet_cidr_blocks": {
+				Description: "CIDR blocks of the subnets in 'aws_subnet_ids'. They aren't sent " +
+					"to OCM, they are only used to check at plan time that the subnets are " +
+					"inside 'machine_cidr'.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
 			"aws_subnet_ids": {
 				Description: "aws subnet ids",
 				Type: types.ListType{
@@ -166,6 +183,7 @@ func (t *ClusterResourceType) GetSchema(ctx context.Context) (result tfsdk.Schem
 				Optional:    true,
 				Computed:    true,
 			},
+			"admin_credentials": adminCredentialsAttribute(ValueCannotBeChangedModifier(t.logger)),
 			"proxy": {
 				Description: "proxy",
 				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
@@ -206,16 +224,36 @@ func (t *ClusterResourceType) GetSchema(ctx context.Context) (result tfsdk.Schem
 				Computed:    true,
 			},
 			"version": {
-				Description: "Identifier of the version of OpenShift, for example 'openshift-v4.1.0'.",
-				Type:        types.StringType,
-				Optional:    true,
-				Computed:    true,
+				Description: "Identifier of the version of OpenShift, for example 'openshift-v4.1.0'. " +
+					"It must be enabled in the selected channel group.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"channel_group": {
+				Description: "Name of the channel group where the version is selected from, " +
+					"one of 'stable', 'fast', 'candidate' or 'nightly'. Default value is 'stable'.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
 			},
 			"state": {
 				Description: "State of the cluster.",
 				Type:        types.StringType,
 				Computed:    true,
 			},
+			"deletion_protection": {
+				Description: "Protects the cluster from being deleted. While it is 'true' the " +
+					"destroy of the resource fails without sending the delete request, and " +
+					"the delete protection of the cluster in OCM is enabled, so that it can't " +
+					"be deleted by other tools either. Changes made to the delete protection " +
+					"outside of Terraform are detected. Default value is 'false'.",
+				Type:     types.BoolType,
+				Optional: true,
+			},
 			"wait": {
 				Description: "Wait till the cluster is ready.",
 				Type:        types.BoolType,
@@ -231,13 +269,15 @@ func (t *ClusterResourceType) NewResource(ctx context.Context,
 	// Cast the provider interface to the specific implementation:
 	parent := p.(*Provider)
 
-	// Get the collection of clusters:
+	// Get the collections of clusters and versions:
 	collection := parent.connection.ClustersMgmt().V1().Clusters()
+	versions := parent.connection.ClustersMgmt().V1().Versions()
 
 	// Create the resource:
 	result = &ClusterResource{
 		logger:     parent.logger,
 		collection: collection,
+		versions:   versions,
 	}
 
 	return
@@ -336,7 +376,10 @@ func createClusterObject(ctx context.Context,
 		builder.Network(network)
 	}
 	if !state.Version.Unknown && !state.Version.Null {
-		builder.Version(cmv1.NewVersion().ID(state.Version.Value))
+		channelGroup := channelGroupOrDefault(state.ChannelGroup)
+		builder.Version(cmv1.NewVersion().
+			ID(versionID(state.Version.Value, channelGroup)).
+			ChannelGroup(channelGroup))
 	}
 
 	proxy := cmv1.NewProxy()
@@ -346,6 +389,12 @@ func createClusterObject(ctx context.Context,
 		builder.Proxy(proxy)
 	}
 
+	builder.Htpasswd(adminCredentialsBuilder(state.AdminCredentials))
+
+	if isDeletionProtected(state.DeletionProtection) {
+		builder.DeleteProtection(cmv1.NewDeleteProtection().Enabled(true))
+	}
+
 	object, err := builder.Build()
 
 	return object, err
@@ -361,6 +410,20 @@ func (r *ClusterResource) Create(ctx context.Context,
 		return
 	}
 
+	// Set the default admin user name and generate the password before creating the cluster,
+	// so that the values sent are the ones saved in the state:
+	err := fillAdminCredentials(state.AdminCredentials)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't generate admin password",
+			fmt.Sprintf(
+				"Can't generate admin password for cluster with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+
 	object, err := createClusterObject(ctx, state, diags)
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -503,12 +566,70 @@ func (r *ClusterResource) Update(ctx context.Context, request tfsdk.UpdateResour
 	}
 	object := update.Body()
 
+	// The admin credentials aren't returned by the server, so take them from the plan:
+	state.AdminCredentials = plan.AdminCredentials
+
 	// Update the state:
 	populateClusterState(object, state)
+
+	// Update the delete protection of the cluster in OCM once the rest of the changes have
+	// been applied, and save the new value only if that succeeds:
+	err = updateDeleteProtection(ctx, r.collection.Cluster(state.ID.Value),
+		state.DeletionProtection, plan.DeletionProtection)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update cluster",
+			fmt.Sprintf(
+				"Can't update delete protection of cluster with identifier '%s': %v",
+				state.ID.Value, err,
+			),
+		)
+	} else {
+		state.DeletionProtection = plan.DeletionProtection
+	}
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
 }
 
+// isDeletionProtected checks if the given 'deletion_protection' attribute is enabled.
+func isDeletionProtected(value types.Bool) bool {
+	return !value.Unknown && !value.Null && value.Value
+}
+
+// deletionProtectionValue returns the value of the 'deletion_protection' attribute that
+// corresponds to the delete protection of the given cluster in OCM. A null value is kept when the
+// protection isn't enabled, as it means the same, and the current value is kept when the server
+// doesn't return the protection.
+func deletionProtectionValue(object *cmv1.Cluster, current types.Bool) types.Bool {
+	protection, ok := object.GetDeleteProtection()
+	if !ok {
+		return current
+	}
+	enabled := protection.Enabled()
+	if !enabled && current.Null {
+		return current
+	}
+	return types.Bool{
+		Value: enabled,
+	}
+}
+
+// updateDeleteProtection mirrors changes of the 'deletion_protection' attribute to the delete
+// protection of the cluster in OCM. Nothing is sent when the value doesn't change.
+func updateDeleteProtection(ctx context.Context, resource *cmv1.ClusterClient,
+	state, plan types.Bool) error {
+	enabled := isDeletionProtected(plan)
+	if isDeletionProtected(state) == enabled {
+		return nil
+	}
+	body, err := cmv1.NewDeleteProtection().Enabled(enabled).Build()
+	if err != nil {
+		return err
+	}
+	_, err = resource.DeleteProtection().Update().Body(body).SendContext(ctx)
+	return err
+}
+
 func (r *ClusterResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
 	response *tfsdk.DeleteResourceResponse) {
 	// Get the state:
@@ -519,6 +640,18 @@ func (r *ClusterResource) Delete(ctx context.Context, request tfsdk.DeleteResour
 		return
 	}
 
+	// Refuse to delete the cluster if it is protected:
+	if isDeletionProtected(state.DeletionProtection) {
+		response.Diagnostics.AddError(
+			"Can't delete cluster",
+			fmt.Sprintf(
+				deletionProtectionFormat,
+				state.ID.Value,
+			),
+		)
+		return
+	}
+
 	// Send the request to delete the cluster:
 	resource := r.collection.Cluster(state.ID.Value)
 	_, err := resource.Delete().SendContext(ctx)
@@ -561,24 +694,78 @@ func (r *ClusterResource) Delete(ctx context.Context, request tfsdk.DeleteResour
 	response.State.RemoveResource(ctx)
 }
 
+func (r *ClusterResource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateResourceConfigRequest, response *tfsdk.ValidateResourceConfigResponse) {
+	network, diags := readClusterNetworkConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	diags = request.Config.GetAttribute(ctx,
+		tftypes.NewAttributePath().WithAttributeName("compute_nodes"), &network.ComputeNodes)
+	response.Diagnostics.Append(diags...)
+	adminCredentials, diags := readClusterAdminCredentialsConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	response.Diagnostics.Append(validateClusterNetwork(network)...)
+	response.Diagnostics.Append(validateClusterAdminCredentials(adminCredentials)...)
+}
+
+func (r *ClusterResource) ModifyPlan(ctx context.Context, request tfsdk.ModifyResourcePlanRequest,
+	response *tfsdk.ModifyResourcePlanResponse) {
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+	product := types.String{}
+	diags := request.Plan.GetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("product"), &product)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	modifyPlanValidateVersion(ctx, r.versions, product.Value, request, response)
+	modifyPlanAdminCredentials(ctx, r.collection, request, response)
+}
+
 func (r *ClusterResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
 	response *tfsdk.ImportResourceStateResponse) {
+	// Find the identifier of the cluster, as it can be imported also using the name or the
+	// external identifier:
+	id, err := importClusterID(ctx, r.collection, request.ID)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf(
+				"Can't find cluster '%s': %v",
+				request.ID, err,
+			),
+		)
+		return
+	}
+
 	// Try to retrieve the object:
-	get, err := r.collection.Cluster(request.ID).Get().SendContext(ctx)
+	get, err := r.collection.Cluster(id).Get().SendContext(ctx)
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Can't find cluster",
 			fmt.Sprintf(
 				"Can't find cluster with identifier '%s': %v",
-				request.ID, err,
+				id, err,
 			),
 		)
 		return
 	}
 	object := get.Body()
 
-	// Save the state:
-	state := &ClusterState{}
+	// Save the state. The subnet CIDR blocks aren't returned by the API, so they start empty,
+	// and the deletion protection starts null so that it is only set when it is enabled:
+	state := &ClusterState{
+		AWSSubnetCIDRBlocks: types.List{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+		DeletionProtection: types.Bool{
+			Null: true,
+		},
+	}
 	populateClusterState(object, state)
 	diags := response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
@@ -630,7 +817,10 @@ func populateClusterState(object *cmv1.Cluster, state *ClusterState) {
 
 	azs, ok := object.Nodes().GetAvailabilityZones()
 	if ok {
-		state.AvailabilityZones.Elems = make([]attr.Value, 0)
+		state.AvailabilityZones = types.List{
+			ElemType: types.StringType,
+			Elems:    make([]attr.Value, 0, len(azs)),
+		}
 		for _, az := range azs {
 			state.AvailabilityZones.Elems = append(state.AvailabilityZones.Elems, types.String{
 				Value: az,
@@ -681,7 +871,10 @@ func populateClusterState(object *cmv1.Cluster, state *ClusterState) {
 
 	subnetIds, ok := object.AWS().GetSubnetIDs()
 	if ok {
-		state.AWSSubnetIDs.Elems = make([]attr.Value, 0)
+		state.AWSSubnetIDs = types.List{
+			ElemType: types.StringType,
+			Elems:    make([]attr.Value, 0, len(subnetIds)),
+		}
 		for _, subnetId := range subnetIds {
 			state.AWSSubnetIDs.Elems = append(state.AWSSubnetIDs.Elems, types.String{
 				Value: subnetId,
@@ -691,6 +884,9 @@ func populateClusterState(object *cmv1.Cluster, state *ClusterState) {
 
 	proxy, ok := object.GetProxy()
 	if ok {
+		if state.Proxy == nil {
+			state.Proxy = &ClusterProxy{}
+		}
 		state.Proxy.HttpProxy = types.String{
 			Value: proxy.HTTPProxy(),
 		}
@@ -738,10 +934,25 @@ func populateClusterState(object *cmv1.Cluster, state *ClusterState) {
 			Null: true,
 		}
 	}
+	channelGroup, ok := object.Version().GetChannelGroup()
+	if ok {
+		state.ChannelGroup = types.String{
+			Value: channelGroup,
+		}
+	} else if state.ChannelGroup.Unknown || state.ChannelGroup.Null {
+		state.ChannelGroup = types.String{
+			Value: defaultChannelGroup,
+		}
+	}
 	version, ok := object.Version().GetID()
 	if ok {
-		state.Version = types.String{
-			Value: version,
+		// Keep the version as written by the user if it is the short form of the identifier
+		// returned by the server, for example '4.12.0' instead of 'openshift-v4.12.0':
+		if state.Version.Unknown || state.Version.Null ||
+			versionID(state.Version.Value, state.ChannelGroup.Value) != version {
+			state.Version = types.String{
+				Value: version,
+			}
 		}
 	} else {
 		state.Version = types.String{
@@ -751,5 +962,5 @@ func populateClusterState(object *cmv1.Cluster, state *ClusterState) {
 	state.State = types.String{
 		Value: string(object.State()),
 	}
-
+	state.DeletionProtection = deletionProtectionValue(object, state.DeletionProtection)
 }
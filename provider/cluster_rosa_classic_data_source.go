@@ -0,0 +1,222 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type ClusterRosaClassicDataSourceType struct {
+}
+
+type ClusterRosaClassicDataSource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+// clusterRosaClassicResourceOnlyAttributes are the attributes of the ROSA classic cluster resource
+// that only control the behaviour of the resource, so they aren't part of the data source.
+var clusterRosaClassicResourceOnlyAttributes = []string{
+	"tags",
+	"deletion_protection",
+	"disable_waiting_in_destroy",
+	"destroy_timeout",
+	"sts_cleanup_report_file",
+	"aws_subnet_cidr_blocks",
+	"admin_credentials",
+}
+
+func (t *ClusterRosaClassicDataSourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	resourceSchema, diags := (&ClusterRosaClassicResourceType{}).GetSchema(ctx)
+	if diags.HasError() {
+		return
+	}
+	for _, name := range clusterRosaClassicResourceOnlyAttributes {
+		delete(resourceSchema.Attributes, name)
+	}
+	attributes := computedAttributes(resourceSchema.Attributes)
+	for name, attribute := range clusterSelectorAttributes() {
+		attributes[name] = attribute
+	}
+	result = tfsdk.Schema{
+		Description: "Existing OpenShift managed cluster using rosa sts, selected by " +
+			"identifier, name or external identifier.",
+		Attributes: attributes,
+	}
+	return
+}
+
+// clusterSelectorAttributes returns the attributes that the cluster data sources use to select
+// the cluster.
+func clusterSelectorAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"id": {
+			Description: "Unique identifier of the cluster. Exactly one of 'id', 'name' " +
+				"or 'external_id' should be set.",
+			Type:     types.StringType,
+			Optional: true,
+			Computed: true,
+		},
+		"name": {
+			Description: "Name of the cluster. Exactly one of 'id', 'name' or " +
+				"'external_id' should be set.",
+			Type:     types.StringType,
+			Optional: true,
+			Computed: true,
+		},
+		"external_id": {
+			Description: "Unique external identifier of the cluster. Exactly one of 'id', " +
+				"'name' or 'external_id' should be set.",
+			Type:     types.StringType,
+			Optional: true,
+			Computed: true,
+		},
+	}
+}
+
+func (t *ClusterRosaClassicDataSourceType) NewDataSource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.DataSource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation:
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the data source:
+	result = &ClusterRosaClassicDataSource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+	return
+}
+
+func (s *ClusterRosaClassicDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
+	response *tfsdk.ReadDataSourceResponse) {
+	// Get the configuration:
+	state := &ClusterRosaClassicDataSourceState{}
+	diags := request.Config.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Find the cluster:
+	id, err := findClusterID(ctx, s.collection, ClusterSelector{
+		ID:         state.ID,
+		Name:       state.Name,
+		ExternalID: state.ExternalID,
+	})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf("Can't find cluster: %v", err),
+		)
+		return
+	}
+	get, err := s.collection.Cluster(id).Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf(
+				"Can't find cluster with identifier '%s': %v",
+				id, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Populate the state using the same code than the resource:
+	clusterState := &ClusterRosaClassicState{
+		AWSSubnetIDs: types.List{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+		AvailabilityZones: types.List{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+		ComputeLabels: types.Map{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+	}
+	err = populateRosaClassicClusterState(ctx, object, clusterState, s.logger, DefaultHttpClient{})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate cluster state",
+			fmt.Sprintf(
+				"Received error %v", err,
+			),
+		)
+		return
+	}
+	state = &ClusterRosaClassicDataSourceState{
+		APIURL:                                 clusterState.APIURL,
+		AWSAccountID:                           clusterState.AWSAccountID,
+		AWSSubnetIDs:                           clusterState.AWSSubnetIDs,
+		AWSPrivateLink:                         clusterState.AWSPrivateLink,
+		AdditionalControlPlaneSecurityGroupIDs: clusterState.AdditionalControlPlaneSecurityGroupIDs,
+		AdditionalInfraSecurityGroupIDs:        clusterState.AdditionalInfraSecurityGroupIDs,
+		AdditionalComputeSecurityGroupIDs:      clusterState.AdditionalComputeSecurityGroupIDs,
+		Sts:                                    clusterState.Sts,
+		CCSEnabled:                             clusterState.CCSEnabled,
+		EtcdEncryption:                         clusterState.EtcdEncryption,
+		AutoScalingEnabled:                     clusterState.AutoScalingEnabled,
+		MinReplicas:                            clusterState.MinReplicas,
+		MaxReplicas:                            clusterState.MaxReplicas,
+		CloudRegion:                            clusterState.CloudRegion,
+		ComputeMachineType:                     clusterState.ComputeMachineType,
+		ComputeLabels:                          clusterState.ComputeLabels,
+		WorkerDiskSize:                         clusterState.WorkerDiskSize,
+		Replicas:                               clusterState.Replicas,
+		ConsoleURL:                             clusterState.ConsoleURL,
+		Domain:                                 clusterState.Domain,
+		HostPrefix:                             clusterState.HostPrefix,
+		ID:                                     clusterState.ID,
+		FIPS:                                   clusterState.FIPS,
+		KMSKeyArn:                              clusterState.KMSKeyArn,
+		AuditLogArn:                            clusterState.AuditLogArn,
+		EC2MetadataHttpTokens:                  clusterState.EC2MetadataHttpTokens,
+		ExternalID:                             clusterState.ExternalID,
+		MachineCIDR:                            clusterState.MachineCIDR,
+		MultiAZ:                                clusterState.MultiAZ,
+		DisableWorkloadMonitoring:              clusterState.DisableWorkloadMonitoring,
+		DisableSCPChecks:                       clusterState.DisableSCPChecks,
+		AvailabilityZones:                      clusterState.AvailabilityZones,
+		Name:                                   clusterState.Name,
+		PodCIDR:                                clusterState.PodCIDR,
+		Properties:                             clusterState.Properties,
+		ServiceCIDR:                            clusterState.ServiceCIDR,
+		Proxy:                                  clusterState.Proxy,
+		State:                                  clusterState.State,
+		Version:                                clusterState.Version,
+		ChannelGroup:                           clusterState.ChannelGroup,
+	}
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
@@ -16,15 +16,10 @@ limitations under the License.
 package provider
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha1"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
-	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -43,6 +38,10 @@ const (
 	awsCloudProvider = "aws"
 	rosaProduct      = "rosa"
 	MinVersion       = "4.10"
+
+	// redactedTrustBundle is the value that the server returns instead of the additional
+	// trust bundle of the cluster.
+	redactedTrustBundle = "REDACTED"
 )
 
 var kmsArnRE = regexp.MustCompile(
@@ -56,6 +55,7 @@ type ClusterRosaClassicResourceType struct {
 type ClusterRosaClassicResource struct {
 	logger     logging.Logger
 	collection *cmv1.ClustersClient
+	versions   *cmv1.VersionsClient
 }
 
 func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
@@ -203,6 +203,27 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					tfsdk.RequiresReplace(),
 				},
 			},
+			"worker_disk_size": {
+				Description: "Size in GiB of the root volume of the compute nodes of the default " +
+					"machine pool. It can't be changed after the cluster is created.",
+				Type:     types.Int64Type,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"ec2_metadata_http_tokens": {
+				Description: "Whether the EC2 instances of the cluster require IMDSv2 (`required`) " +
+					"or also accept IMDSv1 (`optional`). It can't be changed after the cluster " +
+					"is created.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
 			"compute_labels": {
 				Description: "Labels for the default machine pool. Format should be a comma-separated list of 'key=value'. " +
 					"This list will overwrite any modifications made to Node labels on an ongoing basis.",
@@ -219,6 +240,15 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					ValueCannotBeChangedModifier(t.logger),
 				},
 			},
+			"aws_subnet_cidr_blocks": {
+				Description: "CIDR blocks of the subnets in 'aws_subnet_ids'. They aren't sent " +
+					"to OCM, they are only used to check at plan time that the subnets are " +
+					"inside 'machine_cidr'.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
 			"aws_subnet_ids": {
 				Description: "aws subnet ids",
 				Type: types.ListType{
@@ -229,6 +259,42 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					ValueCannotBeChangedModifier(t.logger),
 				},
 			},
+			"additional_control_plane_security_group_ids": {
+				Description: "Identifiers of additional security groups attached to the control " +
+					"plane nodes. They can only be used with 'aws_subnet_ids' and can't be " +
+					"changed after the cluster is created.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"additional_infra_security_group_ids": {
+				Description: "Identifiers of additional security groups attached to the infra " +
+					"nodes. They can only be used with 'aws_subnet_ids' and can't be changed " +
+					"after the cluster is created.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"additional_compute_security_group_ids": {
+				Description: "Identifiers of additional security groups attached to the compute " +
+					"nodes of the default machine pool. They can only be used with " +
+					"'aws_subnet_ids' and can't be changed after the cluster is created.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
 			"kms_key_arn": {
 				Description: "The key ARN is the Amazon Resource Name (ARN) of a AWS KMS (Key Management Service) Key. It is a unique, " +
 					"fully qualified identifier for the AWS KMS Key. A key ARN includes the AWS account, Region, and the key ID.",
@@ -238,6 +304,12 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					ValueCannotBeChangedModifier(t.logger),
 				},
 			},
+			"audit_log_arn": {
+				Description: "ARN of the IAM role used to forward the audit logs of " +
+					"the cluster to AWS CloudWatch. Removing it disables the forwarding.",
+				Type:     types.StringType,
+				Optional: true,
+			},
 			"fips": {
 				Description: "Create cluster that uses FIPS Validated / Modules in Process cryptographic libraries",
 				Type:        types.BoolType,
@@ -274,6 +346,7 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					ValueCannotBeChangedModifier(t.logger),
 				},
 			},
+			"admin_credentials": adminCredentialsAttribute(ValueCannotBeChangedModifier(t.logger)),
 			"proxy": {
 				Description: "proxy",
 				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
@@ -299,9 +372,6 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					},
 				}),
 				Optional: true,
-				PlanModifiers: []tfsdk.AttributePlanModifier{
-					ValueCannotBeChangedModifier(t.logger),
-				},
 			},
 			"service_cidr": {
 				Description: "Block of IP addresses for services.",
@@ -331,15 +401,42 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 				},
 			},
 			"version": {
-				Description: "Identifier of the version of OpenShift, for example 'openshift-v4.1.0'.",
-				Type:        types.StringType,
-				Optional:    true,
-				Computed:    true,
+				Description: "Identifier of the version of OpenShift, for example 'openshift-v4.1.0'. " +
+					"It must be enabled for ROSA in the selected channel group.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
 				// TODO: till AWS will support Managed policies we will not support update versions
 				PlanModifiers: []tfsdk.AttributePlanModifier{
 					ValueCannotBeChangedModifier(t.logger),
 				},
 			},
+			"channel_group": {
+				Description: "Name of the channel group where the version is selected from, " +
+					"one of 'stable', 'fast', 'candidate' or 'nightly'. Default value is 'stable'.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"deletion_protection": {
+				Description: "Protects the cluster from being deleted. While it is 'true' the " +
+					"destroy of the resource fails without sending the delete request, and " +
+					"the delete protection of the cluster in OCM is enabled, so that it can't " +
+					"be deleted by other tools either. Changes made to the delete protection " +
+					"outside of Terraform are detected. Default value is 'false'.",
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"sts_cleanup_report_file": {
+				Description: "Path of a local file where the operator roles, OIDC endpoint URL and " +
+					"thumbprint that are left in AWS after the cluster is destroyed will be written " +
+					"in JSON format. The same information is always reported as a warning.",
+				Type:     types.StringType,
+				Optional: true,
+			},
 			"disable_waiting_in_destroy": {
 				Description: "Disable addressing cluster state in the destroy resource. Default value is false",
 				Type:        types.BoolType,
@@ -365,13 +462,15 @@ func (t *ClusterRosaClassicResourceType) NewResource(ctx context.Context,
 	// Cast the provider interface to the specific implementation:
 	parent := p.(*Provider)
 
-	// Get the collection:
+	// Get the collections:
 	collection := parent.connection.ClustersMgmt().V1().Clusters()
+	versions := parent.connection.ClustersMgmt().V1().Versions()
 
 	// Create the resource:
 	result = &ClusterRosaClassicResource{
 		logger:     parent.logger,
 		collection: collection,
+		versions:   versions,
 	}
 
 	return
@@ -430,6 +529,14 @@ func createClassicClusterObject(ctx context.Context,
 		nodes.ComputeLabels(labels)
 	}
 
+	if !state.WorkerDiskSize.Unknown && !state.WorkerDiskSize.Null {
+		nodes.ComputeRootVolume(
+			cmv1.NewRootVolume().AWS(
+				cmv1.NewAWSVolume().Size(int(state.WorkerDiskSize.Value)),
+			),
+		)
+	}
+
 	if !state.AvailabilityZones.Unknown && !state.AvailabilityZones.Null {
 		azs := make([]string, 0)
 		for _, e := range state.AvailabilityZones.Elems {
@@ -500,6 +607,14 @@ func createClassicClusterObject(ctx context.Context,
 		aws.KMSKeyArn(kmsKeyARN)
 	}
 
+	if isStringSet(state.AuditLogArn) {
+		aws.AuditLog(cmv1.NewAuditLog().RoleArn(state.AuditLogArn.Value))
+	}
+
+	if isStringSet(state.EC2MetadataHttpTokens) {
+		aws.Ec2MetadataHttpTokens(cmv1.Ec2MetadataHttpTokens(state.EC2MetadataHttpTokens.Value))
+	}
+
 	if !state.AWSAccountID.Unknown && !state.AWSAccountID.Null {
 		aws.AccountID(state.AWSAccountID.Value)
 	}
@@ -544,6 +659,25 @@ func createClassicClusterObject(ctx context.Context,
 		aws.SubnetIDs(subnetIds...)
 	}
 
+	if !state.AdditionalControlPlaneSecurityGroupIDs.Unknown &&
+		!state.AdditionalControlPlaneSecurityGroupIDs.Null {
+		aws.AdditionalControlPlaneSecurityGroupIds(
+			stringListValues(state.AdditionalControlPlaneSecurityGroupIDs)...,
+		)
+	}
+	if !state.AdditionalInfraSecurityGroupIDs.Unknown &&
+		!state.AdditionalInfraSecurityGroupIDs.Null {
+		aws.AdditionalInfraSecurityGroupIds(
+			stringListValues(state.AdditionalInfraSecurityGroupIDs)...,
+		)
+	}
+	if !state.AdditionalComputeSecurityGroupIDs.Unknown &&
+		!state.AdditionalComputeSecurityGroupIDs.Null {
+		aws.AdditionalComputeSecurityGroupIds(
+			stringListValues(state.AdditionalComputeSecurityGroupIDs)...,
+		)
+	}
+
 	if !aws.Empty() {
 		builder.AWS(aws)
 	}
@@ -564,7 +698,8 @@ func createClassicClusterObject(ctx context.Context,
 		builder.Network(network)
 	}
 	if !state.Version.Unknown && !state.Version.Null {
-		// TODO: update it to support all cluster versions
+		// The version is checked against the versions catalog at plan time, here we only check
+		// the minimal version supported by ROSA STS clusters:
 		isSupported, err := checkSupportedVersion(state.Version.Value)
 		if err != nil {
 			logger.Error(ctx, "Error validating required cluster version %s\", err)")
@@ -579,7 +714,10 @@ func createClassicClusterObject(ctx context.Context,
 			return nil, errors.New(errHeadline + "\n" + errDecription)
 		}
 		if isSupported {
-			builder.Version(cmv1.NewVersion().ID(state.Version.Value))
+			channelGroup := channelGroupOrDefault(state.ChannelGroup)
+			builder.Version(cmv1.NewVersion().
+				ID(versionID(state.Version.Value, channelGroup)).
+				ChannelGroup(channelGroup))
 		} else {
 			logger.Error(ctx, "Cluster version %s is not supported", state.Version.Value)
 			errDecription := fmt.Sprintf(
@@ -598,12 +736,21 @@ func createClassicClusterObject(ctx context.Context,
 	if state.Proxy != nil {
 		proxy.HTTPProxy(state.Proxy.HttpProxy.Value)
 		proxy.HTTPSProxy(state.Proxy.HttpsProxy.Value)
+		if !state.Proxy.NoProxy.Unknown && !state.Proxy.NoProxy.Null {
+			proxy.NoProxy(state.Proxy.NoProxy.Value)
+		}
 		if !state.Proxy.AdditionalTrustBundle.Unknown && !state.Proxy.AdditionalTrustBundle.Null {
 			builder.AdditionalTrustBundle(state.Proxy.AdditionalTrustBundle.Value)
 		}
 		builder.Proxy(proxy)
 	}
 
+	builder.Htpasswd(adminCredentialsBuilder(state.AdminCredentials))
+
+	if isDeletionProtected(state.DeletionProtection) {
+		builder.DeleteProtection(cmv1.NewDeleteProtection().Enabled(true))
+	}
+
 	object, err := builder.Build()
 	return object, err
 }
@@ -620,7 +767,13 @@ func checkAndSetByoOidcAttributes(ctx context.Context, state *ClusterRosaClassic
 			return nil, errors.New(errHeadline + "\n" + errDescription)
 		}
 		sts.OIDCEndpointURL("https://" + state.Sts.OIDCEndpointURL.Value)
-		sts.OidcPrivateKeySecretArn(state.Sts.OIDCPrivateKeySecretArn.Value)
+		sts.OidcConfig(cmv1.NewOidcConfig().
+			Managed(false).
+			IssuerUrl("https://" + state.Sts.OIDCEndpointURL.Value).
+			SecretArn(state.Sts.OIDCPrivateKeySecretArn.Value))
+	} else if !state.Sts.OIDCConfigID.Unknown && !state.Sts.OIDCConfigID.Null &&
+		state.Sts.OIDCConfigID.Value != "" {
+		sts.OidcConfig(cmv1.NewOidcConfig().ID(state.Sts.OIDCConfigID.Value))
 	}
 	return sts, nil
 }
@@ -630,6 +783,19 @@ func isByoOidcSet(sts *Sts) bool {
 		!sts.OIDCPrivateKeySecretArn.Unknown && !sts.OIDCPrivateKeySecretArn.Null && sts.OIDCPrivateKeySecretArn.Value != ""
 }
 
+// proxyValues returns the proxy settings, using empty strings for the settings that aren't set.
+// Empty values are also what the server expects in order to remove a setting.
+func proxyValues(proxy *Proxy) (httpProxy, httpsProxy, noProxy, trustBundle string) {
+	if proxy == nil {
+		return
+	}
+	httpProxy = proxy.HttpProxy.Value
+	httpsProxy = proxy.HttpsProxy.Value
+	noProxy = proxy.NoProxy.Value
+	trustBundle = proxy.AdditionalTrustBundle.Value
+	return
+}
+
 func (r *ClusterRosaClassicResource) Create(ctx context.Context,
 	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
 	// Get the plan:
@@ -640,6 +806,20 @@ func (r *ClusterRosaClassicResource) Create(ctx context.Context,
 		return
 	}
 
+	// Set the default admin user name and generate the password before creating the cluster,
+	// so that the values sent are the ones saved in the state:
+	err := fillAdminCredentials(state.AdminCredentials)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't generate admin password",
+			fmt.Sprintf(
+				"Can't generate admin password for cluster with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+
 	object, err := createClassicClusterObject(ctx, state, r.logger, diags)
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -738,45 +918,74 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 		return
 	}
 
-	// Send request to update the cluster:
+	// Send request to update the cluster. The limits of autoscaling are always sent together,
+	// and switching from autoscaling to a fixed number of replicas is done sending the number
+	// of replicas:
 	updateNodes := false
 	clusterBuilder := cmv1.NewCluster()
 	clusterNodesBuilder := cmv1.NewClusterNodes()
-	compute, ok := shouldPatchInt(state.Replicas, plan.Replicas)
-	if ok {
-		clusterNodesBuilder = clusterNodesBuilder.Compute(int(compute))
-		updateNodes = true
-	}
-
-	if !plan.AutoScalingEnabled.Unknown && !plan.AutoScalingEnabled.Null && plan.AutoScalingEnabled.Value {
-		// autoscaling enabled
-		autoscaling := cmv1.NewMachinePoolAutoscaling()
-
-		if !plan.MaxReplicas.Unknown && !plan.MaxReplicas.Null {
-			autoscaling = autoscaling.MaxReplicas(int(plan.MaxReplicas.Value))
+	wasAutoscaling := !state.AutoScalingEnabled.Null && state.AutoScalingEnabled.Value
+	if !plan.AutoScalingEnabled.Null && plan.AutoScalingEnabled.Value {
+		if !wasAutoscaling ||
+			!plan.MinReplicas.Equal(state.MinReplicas) ||
+			!plan.MaxReplicas.Equal(state.MaxReplicas) {
+			clusterNodesBuilder = clusterNodesBuilder.AutoscaleCompute(
+				cmv1.NewMachinePoolAutoscaling().
+					MinReplicas(int(plan.MinReplicas.Value)).
+					MaxReplicas(int(plan.MaxReplicas.Value)),
+			)
+			updateNodes = true
 		}
-		if !plan.MinReplicas.Unknown && !plan.MinReplicas.Null {
-			autoscaling = autoscaling.MinReplicas(int(plan.MinReplicas.Value))
+	} else {
+		compute, ok := shouldPatchInt(state.Replicas, plan.Replicas)
+		if !ok && wasAutoscaling && !plan.Replicas.Unknown && !plan.Replicas.Null {
+			compute, ok = plan.Replicas.Value, true
 		}
+		if ok {
+			clusterNodesBuilder = clusterNodesBuilder.Compute(int(compute))
+			updateNodes = true
+		}
+	}
 
-		clusterNodesBuilder = clusterNodesBuilder.AutoscaleCompute(autoscaling)
-		updateNodes = true
-
-	} else {
-		if (!plan.MaxReplicas.Unknown && !plan.MaxReplicas.Null) || (!plan.MinReplicas.Unknown && !plan.MinReplicas.Null) {
-			response.Diagnostics.AddError(
-				"Can't update cluster",
-				fmt.Sprintf(
-					"Can't update MaxReplica and/or MinReplica of cluster when autoscaling is not enabled",
-				),
-			)
-			return
+	// An empty map removes all the labels:
+	if !plan.ComputeLabels.Unknown && !plan.ComputeLabels.Equal(state.ComputeLabels) {
+		labels := map[string]string{}
+		for k, v := range plan.ComputeLabels.Elems {
+			labels[k] = v.(types.String).Value
 		}
+		clusterNodesBuilder = clusterNodesBuilder.ComputeLabels(labels)
+		updateNodes = true
 	}
 
 	if updateNodes {
 		clusterBuilder = clusterBuilder.Nodes(clusterNodesBuilder)
 	}
+
+	// An empty role disables the forwarding of the audit logs:
+	auditLogArn, ok := shouldPatchString(state.AuditLogArn, plan.AuditLogArn)
+	if !ok && plan.AuditLogArn.Null && isStringSet(state.AuditLogArn) {
+		auditLogArn, ok = "", true
+	}
+	if ok {
+		clusterBuilder = clusterBuilder.AWS(
+			cmv1.NewAWS().AuditLog(cmv1.NewAuditLog().RoleArn(auditLogArn)),
+		)
+	}
+	// Removing the proxy is done sending empty values:
+	oldHTTPProxy, oldHTTPSProxy, oldNoProxy, oldTrustBundle := proxyValues(state.Proxy)
+	httpProxy, httpsProxy, noProxy, trustBundle := proxyValues(plan.Proxy)
+	if httpProxy != oldHTTPProxy || httpsProxy != oldHTTPSProxy || noProxy != oldNoProxy {
+		clusterBuilder = clusterBuilder.Proxy(
+			cmv1.NewProxy().
+				HTTPProxy(httpProxy).
+				HTTPSProxy(httpsProxy).
+				NoProxy(noProxy),
+		)
+	}
+	if trustBundle != oldTrustBundle {
+		clusterBuilder = clusterBuilder.AdditionalTrustBundle(trustBundle)
+	}
+
 	clusterSpec, err := clusterBuilder.Build()
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -806,6 +1015,14 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 	state.AutoScalingEnabled = plan.AutoScalingEnabled
 	// update the Replicas with the plan value (important for nil and zero value cases)
 	state.Replicas = plan.Replicas
+	// update the labels with the plan value, so that removed labels don't stay in the state
+	state.ComputeLabels = plan.ComputeLabels
+	// the server doesn't return the trust bundle, so take the proxy from the plan
+	state.Proxy = plan.Proxy
+	// the cleanup report file isn't sent to the server, so take it from the plan
+	state.StsCleanupReportFile = plan.StsCleanupReportFile
+	// the admin credentials aren't returned by the server, so take them from the plan
+	state.AdminCredentials = plan.AdminCredentials
 
 	object := update.Body()
 
@@ -820,6 +1037,22 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 		)
 		return
 	}
+
+	// Update the delete protection of the cluster in OCM once the rest of the changes have
+	// been applied, and save the new value only if that succeeds:
+	err = updateDeleteProtection(ctx, r.collection.Cluster(state.ID.Value),
+		state.DeletionProtection, plan.DeletionProtection)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update cluster",
+			fmt.Sprintf(
+				"Can't update delete protection of cluster with identifier '%s': %v",
+				state.ID.Value, err,
+			),
+		)
+	} else {
+		state.DeletionProtection = plan.DeletionProtection
+	}
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
 }
@@ -834,9 +1067,31 @@ func (r *ClusterRosaClassicResource) Delete(ctx context.Context, request tfsdk.D
 		return
 	}
 
-	// Send the request to delete the cluster:
+	// Refuse to delete the cluster if it is protected:
+	if isDeletionProtected(state.DeletionProtection) {
+		response.Diagnostics.AddError(
+			"Can't delete cluster",
+			fmt.Sprintf(
+				deletionProtectionFormat,
+				state.ID.Value,
+			),
+		)
+		return
+	}
+
+	// Capture the STS resources that will be left in AWS before the cluster is gone:
 	resource := r.collection.Cluster(state.ID.Value)
-	_, err := resource.Delete().SendContext(ctx)
+	var object *cmv1.Cluster
+	get, err := resource.Get().SendContext(ctx)
+	if err != nil {
+		r.logger.Warn(ctx, "Can't get cluster '%s' before deleting it: %v", state.ID.Value, err)
+	} else {
+		object = get.Body()
+	}
+	report := newStsCleanupReport(object, state, DefaultHttpClient{})
+
+	// Send the request to delete the cluster:
+	_, err = resource.Delete().SendContext(ctx)
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Can't delete cluster",
@@ -878,28 +1133,66 @@ func (r *ClusterRosaClassicResource) Delete(ctx context.Context, request tfsdk.D
 		}
 
 	}
+
+	// Report the STS resources that weren't removed:
+	if report != nil {
+		response.Diagnostics.AddWarning(stsCleanupReportSummary, report.Detail())
+		if !state.StsCleanupReportFile.Unknown && !state.StsCleanupReportFile.Null &&
+			state.StsCleanupReportFile.Value != "" {
+			err = report.WriteFile(state.StsCleanupReportFile.Value)
+			if err != nil {
+				response.Diagnostics.AddWarning(
+					"Can't write STS cleanup report",
+					fmt.Sprintf(
+						"Can't write STS cleanup report of cluster with identifier '%s' to file '%s': %v",
+						state.ID.Value, state.StsCleanupReportFile.Value, err,
+					),
+				)
+			}
+		}
+	}
+
 	// Remove the state:
 	response.State.RemoveResource(ctx)
 }
 
 func (r *ClusterRosaClassicResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
 	response *tfsdk.ImportResourceStateResponse) {
+	// Find the identifier of the cluster, as it can be imported also using the name or the
+	// external identifier:
+	id, err := importClusterID(ctx, r.collection, request.ID)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf(
+				"Can't find cluster '%s': %v",
+				request.ID, err,
+			),
+		)
+		return
+	}
+
 	// Try to retrieve the object:
-	get, err := r.collection.Cluster(request.ID).Get().SendContext(ctx)
+	get, err := r.collection.Cluster(id).Get().SendContext(ctx)
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Can't find cluster",
 			fmt.Sprintf(
 				"Can't find cluster with identifier '%s': %v",
-				request.ID, err,
+				id, err,
 			),
 		)
 		return
 	}
 	object := get.Body()
 
-	// Save the state:
-	state := &ClusterRosaClassicState{}
+	// Save the state. The subnet CIDR blocks aren't returned by the API, so they start empty:
+	state := &ClusterRosaClassicState{
+		AWSSubnetCIDRBlocks: types.List{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+	}
 	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{})
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -959,7 +1252,7 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 	}
 
 	labels, ok := object.Nodes().GetComputeLabels()
-	if ok {
+	if ok && len(labels) > 0 {
 		state.ComputeLabels = types.Map{
 			ElemType: types.StringType,
 			Elems:    map[string]attr.Value{},
@@ -969,6 +1262,23 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 				Value: v,
 			}
 		}
+	} else if len(state.ComputeLabels.Elems) > 0 {
+		// The labels were removed:
+		state.ComputeLabels = types.Map{
+			ElemType: types.StringType,
+			Null:     true,
+		}
+	}
+
+	workerDiskSize, ok := object.Nodes().ComputeRootVolume().AWS().GetSize()
+	if ok {
+		state.WorkerDiskSize = types.Int64{
+			Value: int64(workerDiskSize),
+		}
+	} else {
+		state.WorkerDiskSize = types.Int64{
+			Null: true,
+		}
 	}
 
 	disableUserWorkload, ok := object.GetDisableUserWorkloadMonitoring()
@@ -1012,7 +1322,10 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 
 	azs, ok := object.Nodes().GetAvailabilityZones()
 	if ok {
-		state.AvailabilityZones.Elems = make([]attr.Value, 0)
+		state.AvailabilityZones = types.List{
+			ElemType: types.StringType,
+			Elems:    make([]attr.Value, 0, len(azs)),
+		}
 		for _, az := range azs {
 			state.AvailabilityZones.Elems = append(state.AvailabilityZones.Elems, types.String{
 				Value: az,
@@ -1059,11 +1372,38 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 			Value: kmsKeyArn,
 		}
 	}
+	ec2MetadataHttpTokens, ok := object.AWS().GetEc2MetadataHttpTokens()
+	if ok && ec2MetadataHttpTokens != "" {
+		state.EC2MetadataHttpTokens = types.String{
+			Value: string(ec2MetadataHttpTokens),
+		}
+	} else {
+		state.EC2MetadataHttpTokens = types.String{
+			Null: true,
+		}
+	}
+	auditLogArn, ok := object.AWS().AuditLog().GetRoleArn()
+	if ok && auditLogArn != "" {
+		state.AuditLogArn = types.String{
+			Value: auditLogArn,
+		}
+	} else {
+		state.AuditLogArn = types.String{
+			Null: true,
+		}
+	}
 
 	sts, ok := object.AWS().GetSTS()
 	if ok {
 		if state.Sts == nil {
-			state.Sts = &Sts{}
+			state.Sts = &Sts{
+				AccountRolePrefix: types.String{
+					Null: true,
+				},
+				Path: types.String{
+					Null: true,
+				},
+			}
 		}
 		oidc_endpoint_url := sts.OIDCEndpointURL()
 		if strings.HasPrefix(oidc_endpoint_url, "https://") {
@@ -1079,6 +1419,15 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 		state.Sts.SupportRoleArn = types.String{
 			Value: sts.SupportRoleARN(),
 		}
+		state.Sts.OIDCConfigID = types.String{
+			Null: true,
+		}
+		oidcConfigID, ok := sts.OidcConfig().GetID()
+		if ok && oidcConfigID != "" {
+			state.Sts.OIDCConfigID = types.String{
+				Value: oidcConfigID,
+			}
+		}
 		instanceIAMRoles := sts.InstanceIAMRoles()
 		if instanceIAMRoles != nil {
 			state.Sts.InstanceIAMRoles.MasterRoleARN = types.String{
@@ -1113,7 +1462,10 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 
 	subnetIds, ok := object.AWS().GetSubnetIDs()
 	if ok {
-		state.AWSSubnetIDs.Elems = make([]attr.Value, 0)
+		state.AWSSubnetIDs = types.List{
+			ElemType: types.StringType,
+			Elems:    make([]attr.Value, 0, len(subnetIds)),
+		}
 		for _, subnetId := range subnetIds {
 			state.AWSSubnetIDs.Elems = append(state.AWSSubnetIDs.Elems, types.String{
 				Value: subnetId,
@@ -1121,22 +1473,60 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 		}
 	}
 
+	state.AdditionalControlPlaneSecurityGroupIDs = stringListValue(
+		object.AWS().AdditionalControlPlaneSecurityGroupIds(),
+	)
+	state.AdditionalInfraSecurityGroupIDs = stringListValue(
+		object.AWS().AdditionalInfraSecurityGroupIds(),
+	)
+	state.AdditionalComputeSecurityGroupIDs = stringListValue(
+		object.AWS().AdditionalComputeSecurityGroupIds(),
+	)
+
+	var proxyState *Proxy
 	proxy, ok := object.GetProxy()
-	if ok {
-		state.Proxy.HttpProxy = types.String{
-			Value: proxy.HTTPProxy(),
+	if ok && (proxy.HTTPProxy() != "" || proxy.HTTPSProxy() != "" || proxy.NoProxy() != "") {
+		proxyState = &Proxy{
+			HttpProxy: types.String{
+				Value: proxy.HTTPProxy(),
+			},
+			HttpsProxy: types.String{
+				Value: proxy.HTTPSProxy(),
+			},
+			NoProxy: types.String{
+				Null: true,
+			},
+			AdditionalTrustBundle: types.String{
+				Null: true,
+			},
 		}
-		state.Proxy.HttpsProxy = types.String{
-			Value: proxy.HTTPSProxy(),
+		noProxy, ok := proxy.GetNoProxy()
+		if ok && noProxy != "" {
+			proxyState.NoProxy = types.String{
+				Value: noProxy,
+			}
 		}
 	}
-
 	trustBundle, ok := object.GetAdditionalTrustBundle()
-	if ok {
-		state.Proxy.AdditionalTrustBundle = types.String{
+	if ok && trustBundle != "" {
+		if proxyState == nil {
+			proxyState = &Proxy{
+				HttpProxy:  types.String{Null: true},
+				HttpsProxy: types.String{Null: true},
+				NoProxy:    types.String{Null: true},
+			}
+		}
+		proxyState.AdditionalTrustBundle = types.String{
 			Value: trustBundle,
 		}
+		// The server may replace the trust bundle with a placeholder, keep the value that we
+		// have in that case:
+		if trustBundle == redactedTrustBundle && state.Proxy != nil &&
+			isStringSet(state.Proxy.AdditionalTrustBundle) {
+			proxyState.AdditionalTrustBundle = state.Proxy.AdditionalTrustBundle
+		}
 	}
+	state.Proxy = proxyState
 
 	machineCIDR, ok := object.Network().GetMachineCIDR()
 	if ok {
@@ -1178,10 +1568,25 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 			Null: true,
 		}
 	}
+	channelGroup, ok := object.Version().GetChannelGroup()
+	if ok {
+		state.ChannelGroup = types.String{
+			Value: channelGroup,
+		}
+	} else if state.ChannelGroup.Unknown || state.ChannelGroup.Null {
+		state.ChannelGroup = types.String{
+			Value: defaultChannelGroup,
+		}
+	}
 	version, ok := object.Version().GetID()
 	if ok {
-		state.Version = types.String{
-			Value: version,
+		// Keep the version as written by the user if it is the short form of the identifier
+		// returned by the server, for example '4.12.0' instead of 'openshift-v4.12.0':
+		if state.Version.Unknown || state.Version.Null ||
+			versionID(state.Version.Value, state.ChannelGroup.Value) != version {
+			state.Version = types.String{
+				Value: version,
+			}
 		}
 	} else {
 		state.Version = types.String{
@@ -1191,84 +1596,61 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 	state.State = types.String{
 		Value: string(object.State()),
 	}
+	state.DeletionProtection = deletionProtectionValue(object, state.DeletionProtection)
 
 	return nil
 }
 
-type HttpClient interface {
-	Get(url string) (resp *http.Response, err error)
-}
-
-type DefaultHttpClient struct {
-}
-
-func (c DefaultHttpClient) Get(url string) (resp *http.Response, err error) {
-	return http.Get(url)
-}
-
-func getThumbprint(oidcEndpointURL string, httpClient HttpClient) (thumbprint string, err error) {
-	defer func() {
-		if panicErr := recover(); panicErr != nil {
-			fmt.Fprintf(os.Stderr, "recovering from: %q\n", panicErr)
-			thumbprint = ""
-			err = fmt.Errorf("recovering from: %q", panicErr)
-		}
-	}()
-
-	connect, err := url.ParseRequestURI(oidcEndpointURL)
+func checkSupportedVersion(clusterVersion string) (bool, error) {
+	v1, err := parseVersion(clusterVersion)
 	if err != nil {
-		return "", err
+		return false, err
 	}
-
-	response, err := httpClient.Get(fmt.Sprintf("https://%s:443", connect.Host))
+	v2, err := semver.NewVersion(MinVersion)
 	if err != nil {
-		return "", err
-	}
-
-	certChain := response.TLS.PeerCertificates
-
-	// Grab the CA in the chain
-	for _, cert := range certChain {
-		if cert.IsCA {
-			if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
-				hash, err := sha1Hash(cert.Raw)
-				if err != nil {
-					return "", err
-				}
-				return hash, nil
-			}
-		}
+		return false, err
 	}
-
-	// Fall back to using the last certficiate in the chain
-	cert := certChain[len(certChain)-1]
-	return sha1Hash(cert.Raw)
+	// Cluster version is greater than or equal to MinVersion, pre-releases like '4.10.0-fc.1'
+	// are compared using only the major, minor and patch numbers
+	return v1.Core().GreaterThanOrEqual(v2), nil
 }
 
-// sha1Hash computes the SHA1 of the byte array and returns the hex encoding as a string.
-func sha1Hash(data []byte) (string, error) {
-	// nolint:gosec
-	hasher := sha1.New()
-	_, err := hasher.Write(data)
-	if err != nil {
-		return "", fmt.Errorf("Couldn't calculate hash:\n %v", err)
+func (r *ClusterRosaClassicResource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateResourceConfigRequest, response *tfsdk.ValidateResourceConfigResponse) {
+	network, diags := readClusterNetworkConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	topology, diags := readClusterTopologyConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	sts, diags := readClusterStsConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	proxy, diags := readClusterProxyConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	compute, diags := readClusterComputeConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	securityGroups, diags := readClusterSecurityGroupsConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	adminCredentials, diags := readClusterAdminCredentialsConfig(ctx, request.Config)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
 	}
-	hashed := hasher.Sum(nil)
-	return hex.EncodeToString(hashed), nil
+	network.ComputeNodes = topology.ComputeNodes()
+
+	response.Diagnostics.Append(validateClusterSts(sts)...)
+	response.Diagnostics.Append(validateClusterTopology(topology)...)
+	response.Diagnostics.Append(validateClusterNetwork(network)...)
+	response.Diagnostics.Append(validateClusterProxy(proxy)...)
+	response.Diagnostics.Append(validateClusterCompute(compute)...)
+	response.Diagnostics.Append(validateClusterSecurityGroups(securityGroups)...)
+	response.Diagnostics.Append(validateClusterAdminCredentials(adminCredentials)...)
 }
 
-func checkSupportedVersion(clusterVersion string) (bool, error) {
-	rawID := strings.Replace(clusterVersion, "openshift-v", "", 1)
-	v1, err := semver.NewVersion(rawID)
-	if err != nil {
-		return false, err
-	}
-	v2, err := semver.NewVersion(MinVersion)
-	if err != nil {
-		return false, err
-	}
-	//Cluster version is greater than or equal to MinVersion
-	return v1.GreaterThanOrEqual(v2), nil
+func (r *ClusterRosaClassicResource) ModifyPlan(ctx context.Context, request tfsdk.ModifyResourcePlanRequest,
+	response *tfsdk.ModifyResourcePlanResponse) {
+	modifyPlanValidateVersion(ctx, r.versions, rosaProduct, request, response)
+	modifyPlanAccountRoles(ctx, request, response)
+	modifyPlanComputeNodes(ctx, request, response)
+	modifyPlanAdminCredentials(ctx, r.collection, request, response)
 }
 
 func (r *ClusterRosaClassicResource) waitTillClusterIsNotFoundWithTimeout(ctx context.Context, timeout int64,
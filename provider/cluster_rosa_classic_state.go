@@ -21,55 +21,112 @@ import (
 )
 
 type ClusterRosaClassicState struct {
-	APIURL                    types.String `tfsdk:"api_url"`
-	AWSAccountID              types.String `tfsdk:"aws_account_id"`
-	AWSSubnetIDs              types.List   `tfsdk:"aws_subnet_ids"`
-	AWSPrivateLink            types.Bool   `tfsdk:"aws_private_link"`
-	Sts                       *Sts         `tfsdk:"sts"`
-	CCSEnabled                types.Bool   `tfsdk:"ccs_enabled"`
-	EtcdEncryption            types.Bool   `tfsdk:"etcd_encryption"`
-	AutoScalingEnabled        types.Bool   `tfsdk:"autoscaling_enabled"`
-	MinReplicas               types.Int64  `tfsdk:"min_replicas"`
-	MaxReplicas               types.Int64  `tfsdk:"max_replicas"`
-	CloudRegion               types.String `tfsdk:"cloud_region"`
-	ComputeMachineType        types.String `tfsdk:"compute_machine_type"`
-	ComputeLabels             types.Map    `tfsdk:"compute_labels"`
-	Replicas                  types.Int64  `tfsdk:"replicas"`
-	ConsoleURL                types.String `tfsdk:"console_url"`
-	Domain                    types.String `tfsdk:"domain"`
-	HostPrefix                types.Int64  `tfsdk:"host_prefix"`
-	ID                        types.String `tfsdk:"id"`
-	FIPS                      types.Bool   `tfsdk:"fips"`
-	KMSKeyArn                 types.String `tfsdk:"kms_key_arn"`
-	ExternalID                types.String `tfsdk:"external_id"`
-	MachineCIDR               types.String `tfsdk:"machine_cidr"`
-	MultiAZ                   types.Bool   `tfsdk:"multi_az"`
-	DisableWorkloadMonitoring types.Bool   `tfsdk:"disable_workload_monitoring"`
-	DisableSCPChecks          types.Bool   `tfsdk:"disable_scp_checks"`
-	AvailabilityZones         types.List   `tfsdk:"availability_zones"`
-	Name                      types.String `tfsdk:"name"`
-	PodCIDR                   types.String `tfsdk:"pod_cidr"`
-	Properties                types.Map    `tfsdk:"properties"`
-	Tags                      types.Map    `tfsdk:"tags"`
-	ServiceCIDR               types.String `tfsdk:"service_cidr"`
-	Proxy                     *Proxy       `tfsdk:"proxy"`
-	State                     types.String `tfsdk:"state"`
-	Version                   types.String `tfsdk:"version"`
-	DisableWaitingInDestroy   types.Bool   `tfsdk:"disable_waiting_in_destroy"`
-	DestroyTimeout            types.Int64  `tfsdk:"destroy_timeout"`
+	APIURL                                 types.String      `tfsdk:"api_url"`
+	AWSAccountID                           types.String      `tfsdk:"aws_account_id"`
+	AWSSubnetIDs                           types.List        `tfsdk:"aws_subnet_ids"`
+	AWSSubnetCIDRBlocks                    types.List        `tfsdk:"aws_subnet_cidr_blocks"`
+	AWSPrivateLink                         types.Bool        `tfsdk:"aws_private_link"`
+	AdditionalControlPlaneSecurityGroupIDs types.List        `tfsdk:"additional_control_plane_security_group_ids"`
+	AdditionalInfraSecurityGroupIDs        types.List        `tfsdk:"additional_infra_security_group_ids"`
+	AdditionalComputeSecurityGroupIDs      types.List        `tfsdk:"additional_compute_security_group_ids"`
+	Sts                                    *Sts              `tfsdk:"sts"`
+	CCSEnabled                             types.Bool        `tfsdk:"ccs_enabled"`
+	EtcdEncryption                         types.Bool        `tfsdk:"etcd_encryption"`
+	AutoScalingEnabled                     types.Bool        `tfsdk:"autoscaling_enabled"`
+	MinReplicas                            types.Int64       `tfsdk:"min_replicas"`
+	MaxReplicas                            types.Int64       `tfsdk:"max_replicas"`
+	CloudRegion                            types.String      `tfsdk:"cloud_region"`
+	ComputeMachineType                     types.String      `tfsdk:"compute_machine_type"`
+	ComputeLabels                          types.Map         `tfsdk:"compute_labels"`
+	WorkerDiskSize                         types.Int64       `tfsdk:"worker_disk_size"`
+	Replicas                               types.Int64       `tfsdk:"replicas"`
+	ConsoleURL                             types.String      `tfsdk:"console_url"`
+	Domain                                 types.String      `tfsdk:"domain"`
+	HostPrefix                             types.Int64       `tfsdk:"host_prefix"`
+	ID                                     types.String      `tfsdk:"id"`
+	FIPS                                   types.Bool        `tfsdk:"fips"`
+	KMSKeyArn                              types.String      `tfsdk:"kms_key_arn"`
+	AuditLogArn                            types.String      `tfsdk:"audit_log_arn"`
+	EC2MetadataHttpTokens                  types.String      `tfsdk:"ec2_metadata_http_tokens"`
+	ExternalID                             types.String      `tfsdk:"external_id"`
+	MachineCIDR                            types.String      `tfsdk:"machine_cidr"`
+	MultiAZ                                types.Bool        `tfsdk:"multi_az"`
+	DisableWorkloadMonitoring              types.Bool        `tfsdk:"disable_workload_monitoring"`
+	DisableSCPChecks                       types.Bool        `tfsdk:"disable_scp_checks"`
+	AvailabilityZones                      types.List        `tfsdk:"availability_zones"`
+	Name                                   types.String      `tfsdk:"name"`
+	PodCIDR                                types.String      `tfsdk:"pod_cidr"`
+	Properties                             types.Map         `tfsdk:"properties"`
+	Tags                                   types.Map         `tfsdk:"tags"`
+	ServiceCIDR                            types.String      `tfsdk:"service_cidr"`
+	Proxy                                  *Proxy            `tfsdk:"proxy"`
+	AdminCredentials                       *AdminCredentials `tfsdk:"admin_credentials"`
+	State                                  types.String      `tfsdk:"state"`
+	Version                                types.String      `tfsdk:"version"`
+	ChannelGroup                           types.String      `tfsdk:"channel_group"`
+	DeletionProtection                     types.Bool        `tfsdk:"deletion_protection"`
+	DisableWaitingInDestroy                types.Bool        `tfsdk:"disable_waiting_in_destroy"`
+	DestroyTimeout                         types.Int64       `tfsdk:"destroy_timeout"`
+	StsCleanupReportFile                   types.String      `tfsdk:"sts_cleanup_report_file"`
 }
 
 type Sts struct {
 	OIDCEndpointURL         types.String    `tfsdk:"oidc_endpoint_url"`
+	OIDCConfigID            types.String    `tfsdk:"oidc_config_id"`
 	OIDCPrivateKeySecretArn types.String    `tfsdk:"oidc_private_key_secret_arn"`
 	Thumbprint              types.String    `tfsdk:"thumbprint"`
 	RoleARN                 types.String    `tfsdk:"role_arn"`
 	SupportRoleArn          types.String    `tfsdk:"support_role_arn"`
 	InstanceIAMRoles        InstanceIAMRole `tfsdk:"instance_iam_roles"`
 	OperatorRolePrefix      types.String    `tfsdk:"operator_role_prefix"`
+	AccountRolePrefix       types.String    `tfsdk:"account_role_prefix"`
+	Path                    types.String    `tfsdk:"path"`
 }
 
 type InstanceIAMRole struct {
 	MasterRoleARN types.String `tfsdk:"master_role_arn"`
 	WorkerRoleARN types.String `tfsdk:"worker_role_arn"`
 }
+
+type ClusterRosaClassicDataSourceState struct {
+	APIURL                                 types.String `tfsdk:"api_url"`
+	AWSAccountID                           types.String `tfsdk:"aws_account_id"`
+	AWSSubnetIDs                           types.List   `tfsdk:"aws_subnet_ids"`
+	AWSPrivateLink                         types.Bool   `tfsdk:"aws_private_link"`
+	AdditionalControlPlaneSecurityGroupIDs types.List   `tfsdk:"additional_control_plane_security_group_ids"`
+	AdditionalInfraSecurityGroupIDs        types.List   `tfsdk:"additional_infra_security_group_ids"`
+	AdditionalComputeSecurityGroupIDs      types.List   `tfsdk:"additional_compute_security_group_ids"`
+	Sts                                    *Sts         `tfsdk:"sts"`
+	CCSEnabled                             types.Bool   `tfsdk:"ccs_enabled"`
+	EtcdEncryption                         types.Bool   `tfsdk:"etcd_encryption"`
+	AutoScalingEnabled                     types.Bool   `tfsdk:"autoscaling_enabled"`
+	MinReplicas                            types.Int64  `tfsdk:"min_replicas"`
+	MaxReplicas                            types.Int64  `tfsdk:"max_replicas"`
+	CloudRegion                            types.String `tfsdk:"cloud_region"`
+	ComputeMachineType                     types.String `tfsdk:"compute_machine_type"`
+	ComputeLabels                          types.Map    `tfsdk:"compute_labels"`
+	WorkerDiskSize                         types.Int64  `tfsdk:"worker_disk_size"`
+	Replicas                               types.Int64  `tfsdk:"replicas"`
+	ConsoleURL                             types.String `tfsdk:"console_url"`
+	Domain                                 types.String `tfsdk:"domain"`
+	HostPrefix                             types.Int64  `tfsdk:"host_prefix"`
+	ID                                     types.String `tfsdk:"id"`
+	FIPS                                   types.Bool   `tfsdk:"fips"`
+	KMSKeyArn                              types.String `tfsdk:"kms_key_arn"`
+	AuditLogArn                            types.String `tfsdk:"audit_log_arn"`
+	EC2MetadataHttpTokens                  types.String `tfsdk:"ec2_metadata_http_tokens"`
+	ExternalID                             types.String `tfsdk:"external_id"`
+	MachineCIDR                            types.String `tfsdk:"machine_cidr"`
+	MultiAZ                                types.Bool   `tfsdk:"multi_az"`
+	DisableWorkloadMonitoring              types.Bool   `tfsdk:"disable_workload_monitoring"`
+	DisableSCPChecks                       types.Bool   `tfsdk:"disable_scp_checks"`
+	AvailabilityZones                      types.List   `tfsdk:"availability_zones"`
+	Name                                   types.String `tfsdk:"name"`
+	PodCIDR                                types.String `tfsdk:"pod_cidr"`
+	Properties                             types.Map    `tfsdk:"properties"`
+	ServiceCIDR                            types.String `tfsdk:"service_cidr"`
+	Proxy                                  *Proxy       `tfsdk:"proxy"`
+	State                                  types.String `tfsdk:"state"`
+	Version                                types.String `tfsdk:"version"`
+	ChannelGroup                           types.String `tfsdk:"channel_group"`
+}
@@ -21,32 +21,36 @@ import (
 )
 
 type ClusterState struct {
-	APIURL             types.String `tfsdk:"api_url"`
-	AWSAccessKeyID     types.String `tfsdk:"aws_access_key_id"`
-	AWSAccountID       types.String `tfsdk:"aws_account_id"`
-	AWSSecretAccessKey types.String `tfsdk:"aws_secret_access_key"`
-	AWSSubnetIDs       types.List   `tfsdk:"aws_subnet_ids"`
-	AWSPrivateLink     types.Bool   `tfsdk:"aws_private_link"`
-	CCSEnabled         types.Bool   `tfsdk:"ccs_enabled"`
-	CloudProvider      types.String `tfsdk:"cloud_provider"`
-	CloudRegion        types.String `tfsdk:"cloud_region"`
-	ComputeMachineType types.String `tfsdk:"compute_machine_type"`
-	ComputeNodes       types.Int64  `tfsdk:"compute_nodes"`
-	ConsoleURL         types.String `tfsdk:"console_url"`
-	HostPrefix         types.Int64  `tfsdk:"host_prefix"`
-	ID                 types.String `tfsdk:"id"`
-	Product            types.String `tfsdk:"product"`
-	MachineCIDR        types.String `tfsdk:"machine_cidr"`
-	MultiAZ            types.Bool   `tfsdk:"multi_az"`
-	AvailabilityZones  types.List   `tfsdk:"availability_zones"`
-	Name               types.String `tfsdk:"name"`
-	PodCIDR            types.String `tfsdk:"pod_cidr"`
-	Properties         types.Map    `tfsdk:"properties"`
-	ServiceCIDR        types.String `tfsdk:"service_cidr"`
-	Proxy              *Proxy       `tfsdk:"proxy"`
-	State              types.String `tfsdk:"state"`
-	Version            types.String `tfsdk:"version"`
-	Wait               types.Bool   `tfsdk:"wait"`
+	APIURL              types.String      `tfsdk:"api_url"`
+	AWSAccessKeyID      types.String      `tfsdk:"aws_access_key_id"`
+	AWSAccountID        types.String      `tfsdk:"aws_account_id"`
+	AWSSecretAccessKey  types.String      `tfsdk:"aws_secret_access_key"`
+	AWSSubnetIDs        types.List        `tfsdk:"aws_subnet_ids"`
+	AWSSubnetCIDRBlocks types.List        `tfsdk:"aws_subnet_cidr_blocks"`
+	AWSPrivateLink      types.Bool        `tfsdk:"aws_private_link"`
+	CCSEnabled          types.Bool        `tfsdk:"ccs_enabled"`
+	CloudProvider       types.String      `tfsdk:"cloud_provider"`
+	CloudRegion         types.String      `tfsdk:"cloud_region"`
+	DeletionProtection  types.Bool        `tfsdk:"deletion_protection"`
+	ComputeMachineType  types.String      `tfsdk:"compute_machine_type"`
+	ComputeNodes        types.Int64       `tfsdk:"compute_nodes"`
+	ConsoleURL          types.String      `tfsdk:"console_url"`
+	HostPrefix          types.Int64       `tfsdk:"host_prefix"`
+	ID                  types.String      `tfsdk:"id"`
+	Product             types.String      `tfsdk:"product"`
+	MachineCIDR         types.String      `tfsdk:"machine_cidr"`
+	MultiAZ             types.Bool        `tfsdk:"multi_az"`
+	AvailabilityZones   types.List        `tfsdk:"availability_zones"`
+	Name                types.String      `tfsdk:"name"`
+	PodCIDR             types.String      `tfsdk:"pod_cidr"`
+	Properties          types.Map         `tfsdk:"properties"`
+	ServiceCIDR         types.String      `tfsdk:"service_cidr"`
+	Proxy               *ClusterProxy     `tfsdk:"proxy"`
+	AdminCredentials    *AdminCredentials `tfsdk:"admin_credentials"`
+	State               types.String      `tfsdk:"state"`
+	Version             types.String      `tfsdk:"version"`
+	ChannelGroup        types.String      `tfsdk:"channel_group"`
+	Wait                types.Bool        `tfsdk:"wait"`
 }
 
 type Proxy struct {
@@ -55,3 +59,39 @@ type Proxy struct {
 	NoProxy               types.String `tfsdk:"no_proxy"`
 	AdditionalTrustBundle types.String `tfsdk:"additional_trust_bundle"`
 }
+
+// ClusterProxy is the proxy configuration of the 'ocm_cluster' resource and data source, which
+// don't support the additional trust bundle of ROSA clusters.
+type ClusterProxy struct {
+	HttpProxy  types.String `tfsdk:"http_proxy"`
+	HttpsProxy types.String `tfsdk:"https_proxy"`
+	NoProxy    types.String `tfsdk:"no_proxy"`
+}
+
+type ClusterDataSourceState struct {
+	APIURL             types.String  `tfsdk:"api_url"`
+	AWSAccountID       types.String  `tfsdk:"aws_account_id"`
+	AWSSubnetIDs       types.List    `tfsdk:"aws_subnet_ids"`
+	AWSPrivateLink     types.Bool    `tfsdk:"aws_private_link"`
+	CCSEnabled         types.Bool    `tfsdk:"ccs_enabled"`
+	CloudProvider      types.String  `tfsdk:"cloud_provider"`
+	CloudRegion        types.String  `tfsdk:"cloud_region"`
+	ComputeMachineType types.String  `tfsdk:"compute_machine_type"`
+	ComputeNodes       types.Int64   `tfsdk:"compute_nodes"`
+	ConsoleURL         types.String  `tfsdk:"console_url"`
+	HostPrefix         types.Int64   `tfsdk:"host_prefix"`
+	ID                 types.String  `tfsdk:"id"`
+	ExternalID         types.String  `tfsdk:"external_id"`
+	Product            types.String  `tfsdk:"product"`
+	MachineCIDR        types.String  `tfsdk:"machine_cidr"`
+	MultiAZ            types.Bool    `tfsdk:"multi_az"`
+	AvailabilityZones  types.List    `tfsdk:"availability_zones"`
+	Name               types.String  `tfsdk:"name"`
+	PodCIDR            types.String  `tfsdk:"pod_cidr"`
+	Properties         types.Map     `tfsdk:"properties"`
+	ServiceCIDR        types.String  `tfsdk:"service_cidr"`
+	Proxy              *ClusterProxy `tfsdk:"proxy"`
+	State              types.String  `tfsdk:"state"`
+	Version            types.String  `tfsdk:"version"`
+	ChannelGroup       types.String  `tfsdk:"channel_group"`
+}
@@ -0,0 +1,222 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type ClustersDataSourceType struct {
+}
+
+type ClustersDataSource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+func (t *ClustersDataSourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "List of OpenShift managed clusters.",
+		Attributes: map[string]tfsdk.Attribute{
+			"search": {
+				Description: "Search criteria, for example " +
+					"\"product.id = 'rosa' and region.id = 'us-east-1'\".",
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"order": {
+				Description: "Order criteria, for example 'name asc'.",
+				Type:        types.StringType,
+				Optional:    true,
+			},
+			"item": {
+				Description: "Content of the list when there is exactly one item.",
+				Attributes:  tfsdk.SingleNestedAttributes(t.itemAttributes()),
+				Computed:    true,
+			},
+			"items": {
+				Description: "Content of the list.",
+				Attributes: tfsdk.ListNestedAttributes(
+					t.itemAttributes(),
+					tfsdk.ListNestedAttributesOptions{},
+				),
+				Computed: true,
+			},
+		},
+	}
+	return
+}
+
+func (t *ClustersDataSourceType) itemAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"id": {
+			Description: "Unique identifier of the cluster.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"name": {
+			Description: "Name of the cluster.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"state": {
+			Description: "State of the cluster.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"version": {
+			Description: "Identifier of the version of OpenShift, for example 'openshift-v4.1.0'.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"region": {
+			Description: "Cloud region identifier, for example 'us-east-1'.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"product": {
+			Description: "Product identifier, for example 'osd' or 'rosa'.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"multi_az": {
+			Description: "Indicates if the cluster is deployed to multiple availability zones.",
+			Type:        types.BoolType,
+			Computed:    true,
+		},
+		"api_url": {
+			Description: "URL of the API server.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"console_url": {
+			Description: "URL of the console.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+	}
+}
+
+func (t *ClustersDataSourceType) NewDataSource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.DataSource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation:
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the resource:
+	result = &ClustersDataSource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+	return
+}
+
+func (s *ClustersDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
+	response *tfsdk.ReadDataSourceResponse) {
+	// Get the state:
+	state := &ClustersState{}
+	diags := request.Config.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Fetch the list of clusters:
+	var listItems []*cmv1.Cluster
+	listSize := 100
+	listPage := 1
+	listRequest := s.collection.List().Size(listSize)
+	if !state.Search.Unknown && !state.Search.Null {
+		listRequest.Search(state.Search.Value)
+	}
+	if !state.Order.Unknown && !state.Order.Null {
+		listRequest.Order(state.Order.Value)
+	}
+	for {
+		listResponse, err := listRequest.SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't list clusters",
+				err.Error(),
+			)
+			return
+		}
+		if listItems == nil {
+			listItems = make([]*cmv1.Cluster, 0, listResponse.Total())
+		}
+		listResponse.Items().Each(func(listItem *cmv1.Cluster) bool {
+			listItems = append(listItems, listItem)
+			return true
+		})
+		if listResponse.Size() < listSize {
+			break
+		}
+		listPage++
+		listRequest.Page(listPage)
+	}
+
+	// Populate the state:
+	state.Items = make([]*ClusterItemState, len(listItems))
+	for i, listItem := range listItems {
+		state.Items[i] = &ClusterItemState{
+			ID: types.String{
+				Value: listItem.ID(),
+			},
+			Name: types.String{
+				Value: listItem.Name(),
+			},
+			State: types.String{
+				Value: string(listItem.State()),
+			},
+			Version: types.String{
+				Value: listItem.Version().ID(),
+			},
+			Region: types.String{
+				Value: listItem.Region().ID(),
+			},
+			Product: types.String{
+				Value: listItem.Product().ID(),
+			},
+			MultiAZ: types.Bool{
+				Value: listItem.MultiAZ(),
+			},
+			APIURL: types.String{
+				Value: listItem.API().URL(),
+			},
+			ConsoleURL: types.String{
+				Value: listItem.Console().URL(),
+			},
+		}
+	}
+	if len(state.Items) == 1 {
+		state.Item = state.Items[0]
+	} else {
+		state.Item = nil
+	}
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// These are the limits of the size of the root volume of the compute nodes, in GiB, that OCM
+// accepts.
+const (
+	minWorkerDiskSize = 128
+	maxWorkerDiskSize = 16384
+)
+
+// ClusterComputeConfig contains the attributes of the ROSA cluster resource that describe the
+// EC2 instances of the compute nodes and that are checked by the compute validation.
+type ClusterComputeConfig struct {
+	WorkerDiskSize        types.Int64
+	EC2MetadataHttpTokens types.String
+}
+
+// readClusterComputeConfig reads from the configuration the attributes that describe the EC2
+// instances of the compute nodes of a cluster.
+func readClusterComputeConfig(ctx context.Context, config tfsdk.Config) (result *ClusterComputeConfig,
+	diags diag.Diagnostics) {
+	result = &ClusterComputeConfig{}
+	targets := map[string]interface{}{
+		"worker_disk_size":         &result.WorkerDiskSize,
+		"ec2_metadata_http_tokens": &result.EC2MetadataHttpTokens,
+	}
+	for name, target := range targets {
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		diags.Append(config.GetAttribute(ctx, path, target)...)
+	}
+	return
+}
+
+// validateClusterCompute checks that the size of the root volume of the compute nodes is inside
+// the range accepted by OCM and that the IMDS setting is one of the supported values. Values that
+// are unknown or null are ignored.
+func validateClusterCompute(config *ClusterComputeConfig) (diags diag.Diagnostics) {
+	size := config.WorkerDiskSize
+	if !size.Unknown && !size.Null &&
+		(size.Value < minWorkerDiskSize || size.Value > maxWorkerDiskSize) {
+		diags.AddAttributeError(
+			tftypes.NewAttributePath().WithAttributeName("worker_disk_size"),
+			"Invalid disk size",
+			fmt.Sprintf(
+				"Value of 'worker_disk_size' should be between %d and %d GiB, but it is %d",
+				minWorkerDiskSize, maxWorkerDiskSize, size.Value,
+			),
+		)
+	}
+	tokens := config.EC2MetadataHttpTokens
+	if !tokens.Unknown && !tokens.Null {
+		switch cmv1.Ec2MetadataHttpTokens(tokens.Value) {
+		case cmv1.Ec2MetadataHttpTokensOptional, cmv1.Ec2MetadataHttpTokensRequired:
+		default:
+			diags.AddAttributeError(
+				tftypes.NewAttributePath().WithAttributeName("ec2_metadata_http_tokens"),
+				"Invalid IMDS setting",
+				fmt.Sprintf(
+					"Value of 'ec2_metadata_http_tokens' should be '%s' or '%s', but it is '%s'",
+					cmv1.Ec2MetadataHttpTokensOptional, cmv1.Ec2MetadataHttpTokensRequired,
+					tokens.Value,
+				),
+			)
+		}
+	}
+	return
+}
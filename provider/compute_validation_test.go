@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Compute validation", func() {
+	// newConfig returns a configuration where all the attributes are null:
+	newConfig := func() *ClusterComputeConfig {
+		return &ClusterComputeConfig{
+			WorkerDiskSize:        types.Int64{Null: true},
+			EC2MetadataHttpTokens: types.String{Null: true},
+		}
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	It("Accepts null and unknown values", func() {
+		Expect(validateClusterCompute(newConfig())).To(BeEmpty())
+		config := newConfig()
+		config.WorkerDiskSize = types.Int64{Unknown: true}
+		config.EC2MetadataHttpTokens = types.String{Unknown: true}
+		Expect(validateClusterCompute(config)).To(BeEmpty())
+	})
+
+	It("Checks the limits of the disk size", func() {
+		config := newConfig()
+		for _, size := range []int64{minWorkerDiskSize, 300, maxWorkerDiskSize} {
+			config.WorkerDiskSize = types.Int64{Value: size}
+			Expect(validateClusterCompute(config)).To(BeEmpty())
+		}
+		for _, size := range []int64{0, minWorkerDiskSize - 1, maxWorkerDiskSize + 1} {
+			config.WorkerDiskSize = types.Int64{Value: size}
+			Expect(errorPaths(validateClusterCompute(config))).To(ConsistOf(
+				tftypes.NewAttributePath().WithAttributeName("worker_disk_size"),
+			))
+		}
+	})
+
+	It("Checks the IMDS setting", func() {
+		config := newConfig()
+		for _, tokens := range []string{"optional", "required"} {
+			config.EC2MetadataHttpTokens = types.String{Value: tokens}
+			Expect(validateClusterCompute(config)).To(BeEmpty())
+		}
+		for _, tokens := range []string{"", "Required", "v2"} {
+			config.EC2MetadataHttpTokens = types.String{Value: tokens}
+			Expect(errorPaths(validateClusterCompute(config))).To(ConsistOf(
+				tftypes.NewAttributePath().WithAttributeName("ec2_metadata_http_tokens"),
+			))
+		}
+	})
+})
@@ -24,7 +24,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
 )
@@ -220,12 +219,47 @@ func (r *GroupMembershipResource) Delete(ctx context.Context, request tfsdk.Dele
 
 func (r *GroupMembershipResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
 	response *tfsdk.ImportResourceStateResponse) {
-	tfsdk.ResourceImportStatePassthroughID(
-		ctx,
-		tftypes.NewAttributePath().WithAttributeName("id"),
-		request,
-		response,
-	)
+	// The import identifier contains the identifiers of the cluster, the group and the user:
+	values, err := splitImportID(request.ID, "cluster_id", "group", "user")
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid import identifier",
+			fmt.Sprintf("Can't import group membership: %v", err),
+		)
+		return
+	}
+	clusterID, group, user := values[0], values[1], values[2]
+
+	// Try to retrieve the object:
+	resource := r.collection.Cluster(clusterID).Groups().Group(group).
+		Users().
+		User(user)
+	get, err := resource.Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find group membership",
+			fmt.Sprintf(
+				"Can't find user group membership identifier '%s' for "+
+					"cluster '%s' and group '%s': %v",
+				user, clusterID, group, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &GroupMembershipState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		Group: types.String{
+			Value: group,
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
 }
 
 // populateState copies the data from the API object to the Terraform state.
@@ -17,6 +17,11 @@ limitations under the License.
 package provider
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -55,3 +60,158 @@ func shouldPatchString(state, plan types.String) (value string, ok bool) {
 	}
 	return
 }
+
+// stringListValues returns the values of a list of strings. It should only be called when the
+// list is known and not null.
+func stringListValues(list types.List) []string {
+	result := make([]string, len(list.Elems))
+	for i, elem := range list.Elems {
+		result[i] = elem.(types.String).Value
+	}
+	return result
+}
+
+// stringListValue returns a list of strings containing the given values, or a null list if
+// there are no values.
+func stringListValue(values []string) types.List {
+	if len(values) == 0 {
+		return types.List{
+			ElemType: types.StringType,
+			Null:     true,
+		}
+	}
+	result := types.List{
+		ElemType: types.StringType,
+		Elems:    make([]attr.Value, len(values)),
+	}
+	for i, value := range values {
+		result.Elems[i] = types.String{
+			Value: value,
+		}
+	}
+	return result
+}
+
+// stringMapValues returns the values of a map of strings. The result is an empty map when the
+// given map is null, so it can be used to remove all the values of an attribute.
+func stringMapValues(value types.Map) map[string]string {
+	result := make(map[string]string, len(value.Elems))
+	for key, elem := range value.Elems {
+		result[key] = elem.(types.String).Value
+	}
+	return result
+}
+
+// stringMapValue returns a map of strings containing the given values, or a null map if there
+// are no values.
+func stringMapValue(values map[string]string) types.Map {
+	if len(values) == 0 {
+		return types.Map{
+			ElemType: types.StringType,
+			Null:     true,
+		}
+	}
+	result := types.Map{
+		ElemType: types.StringType,
+		Elems:    make(map[string]attr.Value, len(values)),
+	}
+	for key, value := range values {
+		result.Elems[key] = types.String{
+			Value: value,
+		}
+	}
+	return result
+}
+
+// isEmptyMap checks if the given map is known, not null and empty.
+func isEmptyMap(value types.Map) bool {
+	return !value.Unknown && !value.Null && len(value.Elems) == 0
+}
+
+// isEmptyList checks if the given list is known, not null and empty.
+func isEmptyList(value types.List) bool {
+	return !value.Unknown && !value.Null && len(value.Elems) == 0
+}
+
+// optionalString returns the given value as a string, or null if it isn't present.
+func optionalString(value string, ok bool) types.String {
+	if !ok {
+		return types.String{Null: true}
+	}
+	return types.String{
+		Value: value,
+	}
+}
+
+// optionalBool returns the given value as a boolean, or null if it isn't present.
+func optionalBool(value bool, ok bool) types.Bool {
+	if !ok {
+		return types.Bool{Null: true}
+	}
+	return types.Bool{
+		Value: value,
+	}
+}
+
+// computedAttributes returns a copy of the given attributes where all of them, including the
+// nested ones, are computed only. It is used to build the schema of the data sources that return
+// the same information than a resource.
+func computedAttributes(attributes map[string]tfsdk.Attribute) map[string]tfsdk.Attribute {
+	result := make(map[string]tfsdk.Attribute, len(attributes))
+	for name, attribute := range attributes {
+		computed := tfsdk.Attribute{
+			Type:        attribute.Type,
+			Description: attribute.Description,
+			Sensitive:   attribute.Sensitive,
+			Computed:    true,
+		}
+		if attribute.Attributes != nil {
+			nested := computedAttributes(attribute.Attributes.GetAttributes())
+			switch attribute.Attributes.GetNestingMode() {
+			case tfsdk.NestingModeList:
+				computed.Attributes = tfsdk.ListNestedAttributes(
+					nested,
+					tfsdk.ListNestedAttributesOptions{},
+				)
+			default:
+				computed.Attributes = tfsdk.SingleNestedAttributes(nested)
+			}
+		}
+		result[name] = computed
+	}
+	return result
+}
+
+// splitImportID splits an import identifier that contains several values separated by commas,
+// for example '<cluster_id>,<machine_pool_id>'. The names of the expected values are used to
+// check the number of values and to build the error message.
+func splitImportID(importID string, names ...string) ([]string, error) {
+	format := "<" + strings.Join(names, ">,<") + ">"
+	values := strings.Split(importID, ",")
+	if len(values) != len(names) {
+		return nil, fmt.Errorf(
+			"import identifier '%s' should have the format '%s'",
+			importID, format,
+		)
+	}
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+		if values[i] == "" {
+			return nil, fmt.Errorf(
+				"the '%s' part of import identifier '%s' is empty, it should have the format '%s'",
+				names[i], importID, format,
+			)
+		}
+	}
+	return values, nil
+}
+
+// objectStringAttribute returns the value of the given string attribute of an object, or null
+// if it isn't present.
+func objectStringAttribute(object types.Object, name string) types.String {
+	value, ok := object.Attrs[name].(types.String)
+	if !ok {
+		return types.String{Null: true}
+	}
+	return value
+}
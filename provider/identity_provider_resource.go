@@ -25,7 +25,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
 )
@@ -500,6 +499,19 @@ func (r *IdentityProviderResource) Read(ctx context.Context, request tfsdk.ReadR
 	object := get.Body()
 
 	// Copy the identity provider data into the state:
+	r.populateState(object, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// populateState copies the data from the API object to the Terraform state.
+func (r *IdentityProviderResource) populateState(object *cmv1.IdentityProvider, state *IdentityProviderState) {
 	state.Name = types.String{
 		Value: object.Name(),
 	}
@@ -659,14 +671,6 @@ func (r *IdentityProviderResource) Read(ctx context.Context, request tfsdk.ReadR
 			}
 		}
 	}
-	response.Diagnostics.Append(diags...)
-	if response.Diagnostics.HasError() {
-		return
-	}
-
-	// Save the state:
-	diags = response.State.Set(ctx, state)
-	response.Diagnostics.Append(diags...)
 }
 
 func (r *IdentityProviderResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
@@ -706,10 +710,64 @@ func (r *IdentityProviderResource) Delete(ctx context.Context, request tfsdk.Del
 
 func (r *IdentityProviderResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
 	response *tfsdk.ImportResourceStateResponse) {
-	tfsdk.ResourceImportStatePassthroughID(
-		ctx,
-		tftypes.NewAttributePath().WithAttributeName("id"),
-		request,
-		response,
-	)
+	// The import identifier contains the identifier of the cluster and the name or identifier
+	// of the identity provider:
+	values, err := splitImportID(request.ID, "cluster_id", "identity_provider_name_or_id")
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid import identifier",
+			fmt.Sprintf("Can't import identity provider: %v", err),
+		)
+		return
+	}
+	clusterID, nameOrID := values[0], values[1]
+
+	// Find the identity provider, the list is used because the name can't be used to get it
+	// directly:
+	listResponse, err := r.collection.Cluster(clusterID).
+		IdentityProviders().
+		List().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find identity provider",
+			fmt.Sprintf(
+				"Can't list identity providers for cluster '%s': %v",
+				clusterID, err,
+			),
+		)
+		return
+	}
+	var object *cmv1.IdentityProvider
+	listResponse.Items().Each(func(item *cmv1.IdentityProvider) bool {
+		if item.ID() == nameOrID || item.Name() == nameOrID {
+			object = item
+			return false
+		}
+		return true
+	})
+	if object == nil {
+		response.Diagnostics.AddError(
+			"Can't find identity provider",
+			fmt.Sprintf(
+				"Can't find identity provider with name or identifier '%s' for "+
+					"cluster '%s'",
+				nameOrID, clusterID,
+			),
+		)
+		return
+	}
+
+	// Save the state:
+	state := &IdentityProviderState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		ID: types.String{
+			Value: object.ID(),
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
 }
\ No newline at end of file
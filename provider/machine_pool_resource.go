@@ -25,7 +25,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-go/tftypes"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
@@ -472,12 +471,48 @@ func (r *MachinePoolResource) Delete(ctx context.Context, request tfsdk.DeleteRe
 
 func (r *MachinePoolResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
 	response *tfsdk.ImportResourceStateResponse) {
-	tfsdk.ResourceImportStatePassthroughID(
-		ctx,
-		tftypes.NewAttributePath().WithAttributeName("id"),
-		request,
-		response,
-	)
+	// The import identifier contains the identifiers of the cluster and the machine pool:
+	values, err := splitImportID(request.ID, "cluster_id", "machine_pool_id")
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Invalid import identifier",
+			fmt.Sprintf("Can't import machine pool: %v", err),
+		)
+		return
+	}
+	clusterID, machinePoolID := values[0], values[1]
+
+	// Try to retrieve the object:
+	resource := r.collection.Cluster(clusterID).
+		MachinePools().
+		MachinePool(machinePoolID)
+	get, err := resource.Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find machine pool",
+			fmt.Sprintf(
+				"Can't find machine pool with identifier '%s' for "+
+					"cluster '%s': %v",
+				machinePoolID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &MachinePoolState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		Labels: types.Map{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
 }
 
 // populateState copies the data from the API object to the Terraform state.
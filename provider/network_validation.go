@@ -0,0 +1,262 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// These are the values that OCM uses when the network attributes aren't set, and the limits that
+// it accepts.
+const (
+	defaultMachineCIDR = "10.0.0.0/16"
+	defaultServiceCIDR = "172.30.0.0/16"
+	defaultPodCIDR     = "10.128.0.0/14"
+	defaultHostPrefix  = 23
+	minHostPrefix      = 23
+	maxHostPrefix      = 26
+	controlPlaneNodes  = 3
+	singleAZInfraNodes = 2
+	multiAZInfraNodes  = 3
+)
+
+// ClusterNetworkConfig contains the attributes of the cluster resources that are checked by the
+// network validation.
+type ClusterNetworkConfig struct {
+	MachineCIDR  types.String
+	ServiceCIDR  types.String
+	PodCIDR      types.String
+	HostPrefix   types.Int64
+	SubnetCIDRs  types.List
+	MultiAZ      types.Bool
+	ComputeNodes types.Int64
+}
+
+// readClusterNetworkConfig reads from the configuration the network attributes that are common to
+// all the cluster resources. The number of compute nodes is specific to each resource, so it
+// should be filled by the caller.
+func readClusterNetworkConfig(ctx context.Context, config tfsdk.Config) (result *ClusterNetworkConfig,
+	diags diag.Diagnostics) {
+	result = &ClusterNetworkConfig{
+		ComputeNodes: types.Int64{
+			Null: true,
+		},
+	}
+	targets := map[string]interface{}{
+		"machine_cidr":           &result.MachineCIDR,
+		"service_cidr":           &result.ServiceCIDR,
+		"pod_cidr":               &result.PodCIDR,
+		"host_prefix":            &result.HostPrefix,
+		"aws_subnet_cidr_blocks": &result.SubnetCIDRs,
+		"multi_az":               &result.MultiAZ,
+	}
+	for name, target := range targets {
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		diags.Append(config.GetAttribute(ctx, path, target)...)
+	}
+	return
+}
+
+// validateClusterNetwork checks the syntax of the CIDR blocks, that they don't overlap, that the
+// host prefix is inside the range accepted by OCM, that the pod CIDR has room for all the nodes
+// and that the subnets are inside the machine CIDR. Values that are unknown are ignored, and values
+// that are null are replaced by the defaults that OCM uses.
+func validateClusterNetwork(config *ClusterNetworkConfig) (diags diag.Diagnostics) {
+	machineCIDR, machineOK := parseCIDRAttribute("machine_cidr", config.MachineCIDR,
+		defaultMachineCIDR, &diags)
+	serviceCIDR, serviceOK := parseCIDRAttribute("service_cidr", config.ServiceCIDR,
+		defaultServiceCIDR, &diags)
+	podCIDR, podOK := parseCIDRAttribute("pod_cidr", config.PodCIDR,
+		defaultPodCIDR, &diags)
+
+	// Check that the ranges don't overlap. The error is reported in the second attribute of
+	// the pair unless it wasn't explicitly set:
+	type namedCIDR struct {
+		name  string
+		value types.String
+		cidr  *net.IPNet
+		ok    bool
+	}
+	cidrs := []namedCIDR{
+		{"machine_cidr", config.MachineCIDR, machineCIDR, machineOK},
+		{"service_cidr", config.ServiceCIDR, serviceCIDR, serviceOK},
+		{"pod_cidr", config.PodCIDR, podCIDR, podOK},
+	}
+	for i := 0; i < len(cidrs); i++ {
+		for j := i + 1; j < len(cidrs); j++ {
+			first, second := cidrs[i], cidrs[j]
+			if !first.ok || !second.ok || (first.value.Null && second.value.Null) {
+				continue
+			}
+			if !cidrsOverlap(first.cidr, second.cidr) {
+				continue
+			}
+			reported := second.name
+			if second.value.Null {
+				reported = first.name
+			}
+			diags.AddAttributeError(
+				tftypes.NewAttributePath().WithAttributeName(reported),
+				"Overlapping CIDR blocks",
+				fmt.Sprintf(
+					"The value of '%s' (%s) overlaps with the value of '%s' (%s)",
+					first.name, first.cidr, second.name, second.cidr,
+				),
+			)
+		}
+	}
+
+	// Check the host prefix:
+	hostPrefix := int64(defaultHostPrefix)
+	hostPrefixOK := false
+	if !config.HostPrefix.Unknown {
+		hostPrefixOK = true
+		if !config.HostPrefix.Null {
+			hostPrefix = config.HostPrefix.Value
+			if hostPrefix < minHostPrefix || hostPrefix > maxHostPrefix {
+				hostPrefixOK = false
+				diags.AddAttributeError(
+					tftypes.NewAttributePath().WithAttributeName("host_prefix"),
+					"Invalid host prefix",
+					fmt.Sprintf(
+						"Host prefix should be between %d and %d, but it is %d",
+						minHostPrefix, maxHostPrefix, hostPrefix,
+					),
+				)
+			}
+		}
+	}
+
+	// Check that the pod CIDR can be divided in enough blocks of the host prefix size for all the
+	// nodes of the cluster:
+	if podOK && hostPrefixOK && !config.ComputeNodes.Unknown && !config.ComputeNodes.Null &&
+		!config.MultiAZ.Unknown {
+		podPrefix, _ := podCIDR.Mask.Size()
+		required := config.ComputeNodes.Value + controlPlaneNodes + singleAZInfraNodes
+		if config.MultiAZ.Value {
+			required = config.ComputeNodes.Value + controlPlaneNodes + multiAZInfraNodes
+		}
+		if int64(podPrefix) > hostPrefix {
+			diags.AddAttributeError(
+				tftypes.NewAttributePath().WithAttributeName("pod_cidr"),
+				"Pod CIDR too small",
+				fmt.Sprintf(
+					"The prefix length of the pod CIDR (%s) should not be larger "+
+						"than the host prefix (%d)",
+					podCIDR, hostPrefix,
+				),
+			)
+		} else if available := int64(1) << uint(hostPrefix-int64(podPrefix)); available < required {
+			diags.AddAttributeError(
+				tftypes.NewAttributePath().WithAttributeName("pod_cidr"),
+				"Pod CIDR too small",
+				fmt.Sprintf(
+					"The pod CIDR (%s) with host prefix %d has room for %d nodes, "+
+						"but the cluster can have up to %d nodes, including %d compute "+
+						"nodes, the control plane nodes and the infrastructure nodes",
+					podCIDR, hostPrefix, available, required, config.ComputeNodes.Value,
+				),
+			)
+		}
+	}
+
+	// Check that the subnets are inside the machine CIDR:
+	if config.SubnetCIDRs.Unknown || config.SubnetCIDRs.Null {
+		return
+	}
+	for i, element := range config.SubnetCIDRs.Elems {
+		value, ok := element.(types.String)
+		if !ok || value.Unknown || value.Null {
+			continue
+		}
+		path := tftypes.NewAttributePath().
+			WithAttributeName("aws_subnet_cidr_blocks").
+			WithElementKeyInt(i)
+		_, subnetCIDR, err := net.ParseCIDR(value.Value)
+		if err != nil {
+			diags.AddAttributeError(
+				path,
+				"Invalid CIDR block",
+				fmt.Sprintf("Subnet CIDR block '%s' isn't valid: %v", value.Value, err),
+			)
+			continue
+		}
+		if machineOK && !cidrContains(machineCIDR, subnetCIDR) {
+			diags.AddAttributeError(
+				path,
+				"Subnet outside of the machine CIDR",
+				fmt.Sprintf(
+					"Subnet CIDR block '%s' isn't inside the machine CIDR (%s)",
+					value.Value, machineCIDR,
+				),
+			)
+		}
+	}
+	return
+}
+
+// parseCIDRAttribute parses the value of a CIDR attribute, using the default value if it is null.
+// It returns false if the value is unknown or isn't valid, and in the later case it also adds the
+// error to the diagnostics.
+func parseCIDRAttribute(name string, value types.String, defaultValue string,
+	diags *diag.Diagnostics) (result *net.IPNet, ok bool) {
+	if value.Unknown {
+		return
+	}
+	text := defaultValue
+	if !value.Null {
+		text = value.Value
+	}
+	ip, result, err := net.ParseCIDR(text)
+	if err != nil {
+		diags.AddAttributeError(
+			tftypes.NewAttributePath().WithAttributeName(name),
+			"Invalid CIDR block",
+			fmt.Sprintf("Value '%s' of attribute '%s' isn't a valid CIDR block: %v", text, name, err),
+		)
+		return
+	}
+	if ip.To4() == nil {
+		diags.AddAttributeError(
+			tftypes.NewAttributePath().WithAttributeName(name),
+			"Invalid CIDR block",
+			fmt.Sprintf("Value '%s' of attribute '%s' isn't an IPv4 CIDR block", text, name),
+		)
+		return
+	}
+	ok = true
+	return
+}
+
+// cidrsOverlap checks if the given CIDR blocks have any address in common.
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// cidrContains checks if the first CIDR block contains completely the second one.
+func cidrContains(outer, inner *net.IPNet) bool {
+	outerSize, _ := outer.Mask.Size()
+	innerSize, _ := inner.Mask.Size()
+	return outer.Contains(inner.IP) && innerSize >= outerSize
+}
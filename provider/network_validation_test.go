@@ -0,0 +1,142 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Network validation", func() {
+	// newConfig returns a configuration where all the attributes are null, so the defaults of
+	// OCM are used:
+	newConfig := func() *ClusterNetworkConfig {
+		return &ClusterNetworkConfig{
+			MachineCIDR:  types.String{Null: true},
+			ServiceCIDR:  types.String{Null: true},
+			PodCIDR:      types.String{Null: true},
+			HostPrefix:   types.Int64{Null: true},
+			SubnetCIDRs:  types.List{ElemType: types.StringType, Null: true},
+			MultiAZ:      types.Bool{Null: true},
+			ComputeNodes: types.Int64{Null: true},
+		}
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	It("Accepts the defaults", func() {
+		Expect(validateClusterNetwork(newConfig())).To(BeEmpty())
+	})
+
+	It("Ignores unknown values", func() {
+		config := newConfig()
+		config.MachineCIDR = types.String{Unknown: true}
+		config.PodCIDR = types.String{Unknown: true}
+		config.HostPrefix = types.Int64{Unknown: true}
+		config.ComputeNodes = types.Int64{Unknown: true}
+		Expect(validateClusterNetwork(config)).To(BeEmpty())
+	})
+
+	It("Rejects invalid CIDR blocks", func() {
+		config := newConfig()
+		config.MachineCIDR = types.String{Value: "10.0.0.0/33"}
+		diags := validateClusterNetwork(config)
+		Expect(diags.HasError()).To(BeTrue())
+		Expect(errorPaths(diags)).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("machine_cidr"),
+		))
+	})
+
+	It("Rejects IPv6 CIDR blocks", func() {
+		config := newConfig()
+		config.ServiceCIDR = types.String{Value: "fd02::/112"}
+		diags := validateClusterNetwork(config)
+		Expect(errorPaths(diags)).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("service_cidr"),
+		))
+	})
+
+	It("Reports overlaps in the attribute that was set", func() {
+		config := newConfig()
+		config.ServiceCIDR = types.String{Value: "10.0.128.0/17"}
+		diags := validateClusterNetwork(config)
+		Expect(errorPaths(diags)).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("service_cidr"),
+		))
+		Expect(diags[0].Detail()).To(ContainSubstring("10.0.0.0/16"))
+	})
+
+	It("Rejects host prefixes out of range", func() {
+		config := newConfig()
+		config.HostPrefix = types.Int64{Value: 28}
+		diags := validateClusterNetwork(config)
+		Expect(errorPaths(diags)).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("host_prefix"),
+		))
+	})
+
+	It("Checks that the pod CIDR has room for all the nodes", func() {
+		config := newConfig()
+		config.PodCIDR = types.String{Value: "10.128.0.0/20"}
+		config.HostPrefix = types.Int64{Value: 23}
+
+		// A /20 with host prefix 23 has room for 8 nodes, 3 of them for the control plane and
+		// 2 for infrastructure:
+		config.ComputeNodes = types.Int64{Value: 3}
+		Expect(validateClusterNetwork(config)).To(BeEmpty())
+		config.ComputeNodes = types.Int64{Value: 4}
+		diags := validateClusterNetwork(config)
+		Expect(errorPaths(diags)).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("pod_cidr"),
+		))
+
+		// Multiple availability zones need an additional infrastructure node:
+		config.ComputeNodes = types.Int64{Value: 3}
+		config.MultiAZ = types.Bool{Value: true}
+		Expect(validateClusterNetwork(config).HasError()).To(BeTrue())
+	})
+
+	It("Checks that the subnets are inside the machine CIDR", func() {
+		config := newConfig()
+		config.SubnetCIDRs = types.List{
+			ElemType: types.StringType,
+			Elems: []attr.Value{
+				types.String{Value: "10.0.0.0/24"},
+				types.String{Value: "10.1.0.0/24"},
+				types.String{Value: "junk"},
+			},
+		}
+		diags := validateClusterNetwork(config)
+		Expect(errorPaths(diags)).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("aws_subnet_cidr_blocks").WithElementKeyInt(1),
+			tftypes.NewAttributePath().WithAttributeName("aws_subnet_cidr_blocks").WithElementKeyInt(2),
+		))
+	})
+})
@@ -0,0 +1,171 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultThumbprintTimeout is the maximum time that we wait for the OIDC issuer to answer when
+// calculating the thumbprint.
+const defaultThumbprintTimeout = 10 * time.Second
+
+type HttpClient interface {
+	Get(url string) (resp *http.Response, err error)
+}
+
+type DefaultHttpClient struct {
+}
+
+func (c DefaultHttpClient) Get(url string) (resp *http.Response, err error) {
+	client := &http.Client{
+		Timeout: defaultThumbprintTimeout,
+	}
+	return client.Get(url)
+}
+
+// ThumbprintHttpClient is an HTTP client that can be configured with additional trusted CAs, an
+// explicit proxy and a timeout. It is used to retrieve the certificate chain of OIDC issuers that
+// aren't reachable with the default settings.
+type ThumbprintHttpClient struct {
+	client *http.Client
+}
+
+// newThumbprintHttpClient creates an HTTP client that trusts the system CAs and the PEM encoded
+// certificates given in trustedCAs. If proxyURL is empty the proxy is taken from the environment.
+// If timeout is zero the default timeout is used.
+func newThumbprintHttpClient(trustedCAs string, proxyURL string,
+	timeout time.Duration) (result *ThumbprintHttpClient, err error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if trustedCAs != "" && !pool.AppendCertsFromPEM([]byte(trustedCAs)) {
+		err = errors.New("trusted CAs don't contain any valid PEM encoded certificate")
+		return
+	}
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		var parsed *url.URL
+		parsed, err = url.Parse(proxyURL)
+		if err != nil {
+			err = fmt.Errorf("invalid proxy URL '%s': %v", proxyURL, err)
+			return
+		}
+		proxy = http.ProxyURL(parsed)
+	}
+	if timeout <= 0 {
+		timeout = defaultThumbprintTimeout
+	}
+	result = &ThumbprintHttpClient{
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: proxy,
+				TLSClientConfig: &tls.Config{
+					RootCAs:    pool,
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+			Timeout: timeout,
+		},
+	}
+	return
+}
+
+func (c *ThumbprintHttpClient) Get(url string) (resp *http.Response, err error) {
+	return c.client.Get(url)
+}
+
+// fetchCertificateChain connects to the host of the given OIDC issuer URL and returns the
+// certificates presented by the server. The default HTTPS port is used unless the URL contains
+// an explicit one.
+func fetchCertificateChain(issuerURL string, httpClient HttpClient) ([]*x509.Certificate, error) {
+	parsed, err := url.ParseRequestURI(issuerURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("URL '%s' doesn't contain a host", issuerURL)
+	}
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host = net.JoinHostPort(parsed.Hostname(), "443")
+	}
+
+	response, err := httpClient.Get(fmt.Sprintf("https://%s", host))
+	if err != nil {
+		return nil, err
+	}
+	if response.Body != nil {
+		defer response.Body.Close()
+	}
+	if response.TLS == nil || len(response.TLS.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("server '%s' didn't present any TLS certificate", host)
+	}
+	return response.TLS.PeerCertificates, nil
+}
+
+// thumbprintFromChain returns the SHA1 thumbprint of the self signed CA of the chain, or of the
+// last certificate of the chain if there is no such CA.
+func thumbprintFromChain(certChain []*x509.Certificate) (string, error) {
+	if len(certChain) == 0 {
+		return "", errors.New("certificate chain is empty")
+	}
+
+	// Grab the CA in the chain
+	for _, cert := range certChain {
+		if cert.IsCA {
+			if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+				return sha1Hash(cert.Raw)
+			}
+		}
+	}
+
+	// Fall back to using the last certficiate in the chain
+	cert := certChain[len(certChain)-1]
+	return sha1Hash(cert.Raw)
+}
+
+func getThumbprint(oidcEndpointURL string, httpClient HttpClient) (string, error) {
+	certChain, err := fetchCertificateChain(oidcEndpointURL, httpClient)
+	if err != nil {
+		return "", err
+	}
+	return thumbprintFromChain(certChain)
+}
+
+// sha1Hash computes the SHA1 of the byte array and returns the hex encoding as a string.
+func sha1Hash(data []byte) (string, error) {
+	// nolint:gosec
+	hasher := sha1.New()
+	_, err := hasher.Write(data)
+	if err != nil {
+		return "", fmt.Errorf("Couldn't calculate hash:\n %v", err)
+	}
+	hashed := hasher.Sum(nil)
+	return hex.EncodeToString(hashed), nil
+}
@@ -0,0 +1,216 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type OidcThumbprintDataSourceType struct {
+}
+
+type OidcThumbprintDataSource struct {
+	logger logging.Logger
+}
+
+func (t *OidcThumbprintDataSourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Thumbprint of the certificate chain of an OIDC issuer, as needed " +
+			"to create the AWS OIDC identity provider.",
+		Attributes: map[string]tfsdk.Attribute{
+			"issuer_url": {
+				Description: "URL of the OIDC issuer, for example " +
+					"'https://oidc.example.com/my-cluster'.",
+				Type:     types.StringType,
+				Required: true,
+			},
+			"trusted_cas": {
+				Description: "PEM encoded certificates of additional CAs that will " +
+					"be trusted, in addition to the system ones, when connecting " +
+					"to the issuer.",
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"proxy_url": {
+				Description: "URL of the proxy used to connect to the issuer. If not " +
+					"set the proxy is taken from the 'HTTPS_PROXY' and 'NO_PROXY' " +
+					"environment variables.",
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"timeout": {
+				Description: fmt.Sprintf("Maximum number of seconds to wait for "+
+					"the issuer to answer. Default is %d.",
+					int64(defaultThumbprintTimeout/time.Second)),
+				Type:     types.Int64Type,
+				Optional: true,
+			},
+			"thumbprint": {
+				Description: "SHA1 thumbprint of the root CA of the certificate chain.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"certificates": {
+				Description: "Certificates presented by the issuer, starting with " +
+					"the server certificate.",
+				Attributes: tfsdk.ListNestedAttributes(
+					map[string]tfsdk.Attribute{
+						"subject": {
+							Description: "Subject of the certificate.",
+							Type:        types.StringType,
+							Computed:    true,
+						},
+						"issuer": {
+							Description: "Issuer of the certificate.",
+							Type:        types.StringType,
+							Computed:    true,
+						},
+						"not_after": {
+							Description: "Expiration date of the " +
+								"certificate, in RFC 3339 format.",
+							Type:     types.StringType,
+							Computed: true,
+						},
+						"sha1_fingerprint": {
+							Description: "SHA1 fingerprint of the " +
+								"certificate.",
+							Type:     types.StringType,
+							Computed: true,
+						},
+						"is_ca": {
+							Description: "Indicates if the certificate " +
+								"is a CA.",
+							Type:     types.BoolType,
+							Computed: true,
+						},
+					},
+					tfsdk.ListNestedAttributesOptions{},
+				),
+				Computed: true,
+			},
+		},
+	}
+	return
+}
+
+func (s *OidcThumbprintDataSource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateDataSourceConfigRequest, response *tfsdk.ValidateDataSourceConfigResponse) {
+	var timeout types.Int64
+	diags := request.Config.GetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("timeout"), &timeout)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	if !timeout.Null && !timeout.Unknown && timeout.Value <= 0 {
+		response.Diagnostics.AddAttributeError(
+			tftypes.NewAttributePath().WithAttributeName("timeout"),
+			"Invalid timeout",
+			fmt.Sprintf("Timeout must be a positive number of seconds, but it is %d", timeout.Value),
+		)
+	}
+}
+
+func (t *OidcThumbprintDataSourceType) NewDataSource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.DataSource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation:
+	parent := p.(*Provider)
+
+	// Create the data source:
+	result = &OidcThumbprintDataSource{
+		logger: parent.logger,
+	}
+	return
+}
+
+func (s *OidcThumbprintDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
+	response *tfsdk.ReadDataSourceResponse) {
+	// Get the configuration:
+	state := &OidcThumbprintState{}
+	diags := request.Config.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Create the HTTP client:
+	var timeout time.Duration
+	if !state.Timeout.Null && !state.Timeout.Unknown {
+		timeout = time.Duration(state.Timeout.Value) * time.Second
+	}
+	httpClient, err := newThumbprintHttpClient(state.TrustedCAs.Value, state.ProxyURL.Value, timeout)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create HTTP client",
+			fmt.Sprintf("Can't create HTTP client for OIDC issuer '%s': %v", state.IssuerURL.Value, err),
+		)
+		return
+	}
+
+	// Fetch the certificate chain:
+	certChain, err := fetchCertificateChain(state.IssuerURL.Value, httpClient)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't get certificate chain",
+			fmt.Sprintf("Can't get certificate chain of OIDC issuer '%s': %v", state.IssuerURL.Value, err),
+		)
+		return
+	}
+	thumbprint, err := thumbprintFromChain(certChain)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't calculate thumbprint",
+			fmt.Sprintf("Can't calculate thumbprint of OIDC issuer '%s': %v", state.IssuerURL.Value, err),
+		)
+		return
+	}
+
+	// Populate the state:
+	state.Thumbprint = types.String{
+		Value: thumbprint,
+	}
+	state.Certificates = make([]*OidcCertificateState, len(certChain))
+	for i, cert := range certChain {
+		fingerprint, err := sha1Hash(cert.Raw)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't calculate fingerprint",
+				fmt.Sprintf("Can't calculate fingerprint of certificate '%s': %v", cert.Subject, err),
+			)
+			return
+		}
+		state.Certificates[i] = &OidcCertificateState{
+			Subject:         cert.Subject.String(),
+			Issuer:          cert.Issuer.String(),
+			NotAfter:        cert.NotAfter.UTC().Format(time.RFC3339),
+			SHA1Fingerprint: fingerprint,
+			IsCA:            cert.IsCA,
+		}
+	}
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
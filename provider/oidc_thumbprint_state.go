@@ -0,0 +1,38 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type OidcThumbprintState struct {
+	IssuerURL    types.String            `tfsdk:"issuer_url"`
+	TrustedCAs   types.String            `tfsdk:"trusted_cas"`
+	ProxyURL     types.String            `tfsdk:"proxy_url"`
+	Timeout      types.Int64             `tfsdk:"timeout"`
+	Thumbprint   types.String            `tfsdk:"thumbprint"`
+	Certificates []*OidcCertificateState `tfsdk:"certificates"`
+}
+
+type OidcCertificateState struct {
+	Subject         string `tfsdk:"subject"`
+	Issuer          string `tfsdk:"issuer"`
+	NotAfter        string `tfsdk:"not_after"`
+	SHA1Fingerprint string `tfsdk:"sha1_fingerprint"`
+	IsCA            bool   `tfsdk:"is_ca"`
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/sha1" // nolint:gosec
+	"encoding/hex"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("OIDC thumbprint", func() {
+	var server *httptest.Server
+	var trustedCAs string
+	var expected string
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		cert := server.Certificate()
+		trustedCAs = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		}))
+		hash := sha1.Sum(cert.Raw) // nolint:gosec
+		expected = hex.EncodeToString(hash[:])
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Calculates the thumbprint when the CA is trusted", func() {
+		client, err := newThumbprintHttpClient(trustedCAs, "", 0)
+		Expect(err).ToNot(HaveOccurred())
+		chain, err := fetchCertificateChain(server.URL+"/my-cluster", client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(chain).To(HaveLen(1))
+		thumbprint, err := thumbprintFromChain(chain)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(thumbprint).To(Equal(expected))
+	})
+
+	It("Fails when the CA isn't trusted", func() {
+		client, err := newThumbprintHttpClient("", "", 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = getThumbprint(server.URL, client)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("certificate"))
+	})
+
+	It("Fails if the trusted CAs don't contain any certificate", func() {
+		_, err := newThumbprintHttpClient("junk", "", 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Fails if the URL doesn't contain a host", func() {
+		client, err := newThumbprintHttpClient(trustedCAs, "", 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = getThumbprint("/my-cluster", client)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Uses the given proxy", func() {
+		var connects int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodConnect {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			atomic.AddInt32(&connects, 1)
+			upstream, err := net.Dial("tcp", r.Host)
+			if err != nil {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			defer upstream.Close()
+			w.WriteHeader(http.StatusOK)
+			downstream, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			defer downstream.Close()
+			go io.Copy(upstream, downstream) // nolint:errcheck
+			io.Copy(downstream, upstream)    // nolint:errcheck
+		}))
+		defer proxy.Close()
+
+		client, err := newThumbprintHttpClient(trustedCAs, proxy.URL, 0)
+		Expect(err).ToNot(HaveOccurred())
+		thumbprint, err := getThumbprint(server.URL, client)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(thumbprint).To(Equal(expected))
+		Expect(atomic.LoadInt32(&connects)).To(BeNumerically("==", 1))
+	})
+
+	It("Fails if the proxy URL is invalid", func() {
+		_, err := newThumbprintHttpClient(trustedCAs, "http://proxy.example.com:junk", 0)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Honours the timeout", func() {
+		// Create a listener that accepts connections but never answers:
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		defer listener.Close()
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+			}
+		}()
+
+		client, err := newThumbprintHttpClient(trustedCAs, "", 100*time.Millisecond)
+		Expect(err).ToNot(HaveOccurred())
+		start := time.Now()
+		_, err = getThumbprint("https://"+listener.Addr().String(), client)
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	semver "github.com/hashicorp/go-version"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// majorMinorVersion returns the given version without the patch number and pre-release, for
+// example '4.12' for '4.12.5-fc.1'.
+func majorMinorVersion(version *semver.Version) (*semver.Version, error) {
+	segments := version.Segments()
+	return semver.NewVersion(fmt.Sprintf("%d.%d", segments[0], segments[1]))
+}
+
+// isOperatorCompatible checks if the given version of OpenShift needs the given operator, using
+// the minimum and maximum versions of the operator. Only the major and minor numbers of the
+// versions are compared, so that for example '4.11.5' is compatible with a maximum version '4.11'.
+func isOperatorCompatible(operator *cmv1.STSOperator, version *semver.Version) (bool, error) {
+	current, err := majorMinorVersion(version)
+	if err != nil {
+		return false, err
+	}
+	if operator.MinVersion() != "" {
+		minVersion, err := semver.NewVersion(operator.MinVersion())
+		if err != nil {
+			return false, fmt.Errorf(
+				"minimum version '%s' of operator '%s' isn't valid: %v",
+				operator.MinVersion(), operator.Name(), err,
+			)
+		}
+		minVersion, err = majorMinorVersion(minVersion)
+		if err != nil {
+			return false, err
+		}
+		if current.LessThan(minVersion) {
+			return false, nil
+		}
+	}
+	if operator.MaxVersion() != "" {
+		maxVersion, err := semver.NewVersion(operator.MaxVersion())
+		if err != nil {
+			return false, fmt.Errorf(
+				"maximum version '%s' of operator '%s' isn't valid: %v",
+				operator.MaxVersion(), operator.Name(), err,
+			)
+		}
+		maxVersion, err = majorMinorVersion(maxVersion)
+		if err != nil {
+			return false, err
+		}
+		if current.GreaterThan(maxVersion) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// roleNameFromARN returns the name of the IAM role of the given ARN, without the path.
+func roleNameFromARN(arn string) string {
+	parsed, err := parseARN(arn)
+	if err != nil {
+		return ""
+	}
+	return parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:]
+}
+
+// clusterOperatorRolePrefix returns the operator role prefix of the given cluster. OCM doesn't
+// always return it, so when it is empty it is derived from the names of the operator roles of the
+// cluster, that have the form 'prefix-namespace-name'.
+func clusterOperatorRolePrefix(cluster *cmv1.Cluster) string {
+	sts := cluster.AWS().STS()
+	if sts.OperatorRolePrefix() != "" {
+		return sts.OperatorRolePrefix()
+	}
+	for _, role := range sts.OperatorIAMRoles() {
+		name := roleNameFromARN(role.RoleARN())
+		suffix := fmt.Sprintf("-%s-%s", role.Namespace(), role.Name())
+		if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return ""
+}
+
+// clusterAccountRolePrefix returns the account role prefix of the given cluster, derived from the
+// name of its installer role. It returns an empty string if the installer role doesn't follow the
+// naming convention of the account roles.
+func clusterAccountRolePrefix(cluster *cmv1.Cluster) string {
+	name := roleNameFromARN(cluster.AWS().STS().RoleARN())
+	suffix := "-" + accountRoles[0].suffix
+	if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+		return strings.TrimSuffix(name, suffix)
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	semver "github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Operator roles", func() {
+	Describe("Version compatibility", func() {
+		// check returns true if the operator with the given minimum and maximum versions is
+		// needed by the given version of OpenShift:
+		check := func(minVersion, maxVersion, version string) bool {
+			operator, err := cmv1.NewSTSOperator().
+				Name("my-operator").
+				MinVersion(minVersion).
+				MaxVersion(maxVersion).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := semver.NewVersion(version)
+			Expect(err).ToNot(HaveOccurred())
+			compatible, err := isOperatorCompatible(operator, parsed)
+			Expect(err).ToNot(HaveOccurred())
+			return compatible
+		}
+
+		It("Accepts operators without limits", func() {
+			Expect(check("", "", "4.12.5")).To(BeTrue())
+		})
+
+		It("Checks the minimum version", func() {
+			Expect(check("4.10", "", "4.9.59")).To(BeFalse())
+			Expect(check("4.10", "", "4.10.0")).To(BeTrue())
+			Expect(check("4.10", "", "4.10.0-fc.1")).To(BeTrue())
+		})
+
+		It("Checks the maximum version using only the major and minor numbers", func() {
+			Expect(check("", "4.11", "4.11.5")).To(BeTrue())
+			Expect(check("", "4.11", "4.12.0")).To(BeFalse())
+		})
+
+		It("Fails if the limits aren't valid", func() {
+			operator, err := cmv1.NewSTSOperator().MinVersion("junk").Build()
+			Expect(err).ToNot(HaveOccurred())
+			_, err = isOperatorCompatible(operator, semver.Must(semver.NewVersion("4.12")))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Prefixes", func() {
+		It("Uses the operator role prefix of the cluster", func() {
+			cluster, err := cmv1.NewCluster().
+				AWS(cmv1.NewAWS().
+					STS(cmv1.NewSTS().
+						OperatorRolePrefix("my-prefix"))).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterOperatorRolePrefix(cluster)).To(Equal("my-prefix"))
+		})
+
+		It("Derives the operator role prefix from the operator roles", func() {
+			cluster, err := cmv1.NewCluster().
+				AWS(cmv1.NewAWS().
+					STS(cmv1.NewSTS().
+						OperatorIAMRoles(cmv1.NewOperatorIAMRole().
+							Name("cloud-credentials").
+							Namespace("openshift-ingress-operator").
+							RoleARN("arn:aws:iam::123456789012:role/rosa/my-prefix-openshift-ingress-operator-cloud-credentials")))).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterOperatorRolePrefix(cluster)).To(Equal("my-prefix"))
+		})
+
+		It("Returns an empty operator role prefix if it can't be derived", func() {
+			cluster, err := cmv1.NewCluster().
+				AWS(cmv1.NewAWS().
+					STS(cmv1.NewSTS().
+						OperatorIAMRoles(cmv1.NewOperatorIAMRole().
+							Name("cloud-credentials").
+							Namespace("openshift-ingress-operator").
+							RoleARN("arn:aws:iam::123456789012:role/my-role")))).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterOperatorRolePrefix(cluster)).To(BeEmpty())
+		})
+
+		It("Derives the account role prefix from the installer role", func() {
+			cluster, err := cmv1.NewCluster().
+				AWS(cmv1.NewAWS().
+					STS(cmv1.NewSTS().
+						RoleARN("arn:aws:iam::123456789012:role/my-account-Installer-Role"))).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterAccountRolePrefix(cluster)).To(Equal("my-account"))
+		})
+	})
+})
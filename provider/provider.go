@@ -209,11 +209,14 @@ func (p *Provider) Configure(ctx context.Context, request tfsdk.ConfigureProvide
 func (p *Provider) GetResources(ctx context.Context) (result map[string]tfsdk.ResourceType,
 	diags diag.Diagnostics) {
 	result = map[string]tfsdk.ResourceType{
-		"ocm_cluster":              &ClusterResourceType{},
+		"ocm_cluster":              &ClusterResourceType{p.logger},
 		"ocm_cluster_rosa_classic": &ClusterRosaClassicResourceType{p.logger},
+		"ocm_cluster_ingress":      &ClusterIngressResourceType{p.logger},
+		"ocm_cluster_autoscaler":   &ClusterAutoscalerResourceType{p.logger},
 		"ocm_group_membership":     &GroupMembershipResourceType{},
 		"ocm_identity_provider":    &IdentityProviderResourceType{},
 		"ocm_machine_pool":         &MachinePoolResourceType{p.logger},
+		"ocm_rosa_oidc_config":     &RosaOidcConfigResourceType{},
 		"ocm_cluster_wait":         &ClusterWaiterResourceType{},
 	}
 	return
@@ -223,11 +226,16 @@ func (p *Provider) GetResources(ctx context.Context) (result map[string]tfsdk.Re
 func (p *Provider) GetDataSources(ctx context.Context) (result map[string]tfsdk.DataSourceType,
 	diags diag.Diagnostics) {
 	result = map[string]tfsdk.DataSourceType{
-		"ocm_cloud_providers":     &CloudProvidersDataSourceType{},
-		"ocm_rosa_operator_roles": &RosaOperatorRolesDataSourceType{},
-		"ocm_groups":              &GroupsDataSourceType{},
-		"ocm_machine_types":       &MachineTypesDataSourceType{},
-		"ocm_versions":            &VersionsDataSourceType{},
+		"ocm_cloud_providers":      &CloudProvidersDataSourceType{},
+		"ocm_cluster":              &ClusterDataSourceType{},
+		"ocm_cluster_rosa_classic": &ClusterRosaClassicDataSourceType{},
+		"ocm_clusters":             &ClustersDataSourceType{},
+		"ocm_rosa_operator_roles":  &RosaOperatorRolesDataSourceType{},
+		"ocm_groups":               &GroupsDataSourceType{},
+		"ocm_machine_types":        &MachineTypesDataSourceType{},
+		"ocm_oidc_thumbprint":      &OidcThumbprintDataSourceType{},
+		"ocm_rosa_account_roles":   &RosaAccountRolesDataSourceType{},
+		"ocm_versions":             &VersionsDataSourceType{},
 	}
 	return
 }
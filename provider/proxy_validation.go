@@ -0,0 +1,170 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// ClusterProxyConfig contains the attributes of the ROSA cluster resource that are checked by the
+// proxy validation.
+type ClusterProxyConfig struct {
+	Proxy        *Proxy
+	AWSSubnetIDs types.List
+}
+
+// readClusterProxyConfig reads from the configuration the proxy settings and the subnets of a
+// cluster. The proxy is nil when the block isn't set or when it isn't known yet.
+func readClusterProxyConfig(ctx context.Context, config tfsdk.Config) (result *ClusterProxyConfig,
+	diags diag.Diagnostics) {
+	result = &ClusterProxyConfig{}
+	var proxy types.Object
+	diags.Append(config.GetAttribute(
+		ctx, tftypes.NewAttributePath().WithAttributeName("proxy"), &proxy,
+	)...)
+	diags.Append(config.GetAttribute(
+		ctx, tftypes.NewAttributePath().WithAttributeName("aws_subnet_ids"), &result.AWSSubnetIDs,
+	)...)
+	if diags.HasError() || proxy.Null || proxy.Unknown {
+		return
+	}
+	result.Proxy = &Proxy{
+		HttpProxy:             objectStringAttribute(proxy, "http_proxy"),
+		HttpsProxy:            objectStringAttribute(proxy, "https_proxy"),
+		NoProxy:               objectStringAttribute(proxy, "no_proxy"),
+		AdditionalTrustBundle: objectStringAttribute(proxy, "additional_trust_bundle"),
+	}
+	return
+}
+
+// validateClusterProxy checks that the proxy URLs are well formed, that the additional trust
+// bundle contains only valid PEM encoded certificates and that the proxy is only used with
+// subnets provided by the user, as the proxy has to be reachable from the VPC of the cluster.
+// Values that are unknown are ignored.
+func validateClusterProxy(config *ClusterProxyConfig) (diags diag.Diagnostics) {
+	if config.Proxy == nil {
+		return
+	}
+	path := tftypes.NewAttributePath().WithAttributeName("proxy")
+	if config.AWSSubnetIDs.Null {
+		diags.AddAttributeError(
+			path,
+			"Proxy requires subnets",
+			"Attribute 'proxy' can only be used when the cluster is installed in existing "+
+				"subnets, set them with the 'aws_subnet_ids' attribute",
+		)
+	}
+	checkProxyURL(path.WithAttributeName("http_proxy"), config.Proxy.HttpProxy,
+		[]string{"http"}, &diags)
+	checkProxyURL(path.WithAttributeName("https_proxy"), config.Proxy.HttpsProxy,
+		[]string{"http", "https"}, &diags)
+	checkTrustBundle(path.WithAttributeName("additional_trust_bundle"),
+		config.Proxy.AdditionalTrustBundle, &diags)
+	return
+}
+
+// checkProxyURL checks that the given value is an absolute URL with one of the given schemes and
+// a host. Empty values are accepted because they mean that there is no proxy for that protocol.
+func checkProxyURL(path *tftypes.AttributePath, value types.String, schemes []string,
+	diags *diag.Diagnostics) {
+	if !isStringSet(value) {
+		return
+	}
+	parsed, err := url.ParseRequestURI(value.Value)
+	if err != nil || parsed.Host == "" {
+		diags.AddAttributeError(
+			path,
+			"Invalid proxy URL",
+			fmt.Sprintf(
+				"Value '%s' isn't a valid URL, it should have the form 'http://host:port'",
+				value.Value,
+			),
+		)
+		return
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			return
+		}
+	}
+	diags.AddAttributeError(
+		path,
+		"Invalid proxy URL",
+		fmt.Sprintf(
+			"Scheme of URL '%s' should be '%s', but it is '%s'",
+			value.Value, strings.Join(schemes, "' or '"), parsed.Scheme,
+		),
+	)
+}
+
+// checkTrustBundle checks that the given value contains one or more PEM encoded X.509
+// certificates and nothing else.
+func checkTrustBundle(path *tftypes.AttributePath, value types.String, diags *diag.Diagnostics) {
+	if !isStringSet(value) {
+		return
+	}
+	rest := []byte(value.Value)
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		count++
+		if block.Type != "CERTIFICATE" {
+			diags.AddAttributeError(
+				path,
+				"Invalid trust bundle",
+				fmt.Sprintf(
+					"Block %d of the trust bundle should be a 'CERTIFICATE', but it is '%s'",
+					count, block.Type,
+				),
+			)
+			return
+		}
+		_, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			diags.AddAttributeError(
+				path,
+				"Invalid trust bundle",
+				fmt.Sprintf(
+					"Certificate %d of the trust bundle can't be parsed: %v",
+					count, err,
+				),
+			)
+			return
+		}
+	}
+	if count == 0 || strings.TrimSpace(string(rest)) != "" {
+		diags.AddAttributeError(
+			path,
+			"Invalid trust bundle",
+			"The trust bundle should contain only PEM encoded X.509 certificates",
+		)
+	}
+}
@@ -0,0 +1,335 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+// Tags that the ROSA command line tool adds to the account roles:
+const (
+	redHatManagedTag    = "red-hat-managed"
+	roleOpenShiftVerTag = "rosa_openshift_version"
+	rolePrefixTag       = "rosa_role_prefix"
+	roleTypeTag         = "rosa_role_type"
+)
+
+type RosaAccountRolesDataSourceType struct {
+}
+
+type RosaAccountRolesDataSource struct {
+	logger      logging.Logger
+	connection  *sdk.Connection
+	stsPolicies *cmv1.AWSSTSPoliciesInquiryClient
+}
+
+func (t *RosaAccountRolesDataSourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Names and policies of the account roles needed by ROSA STS clusters.",
+		Attributes: map[string]tfsdk.Attribute{
+			"account_role_prefix": {
+				Description: fmt.Sprintf("Prefix of the names of the account roles. "+
+					"Default is '%s'.", DefaultAccountRolePrefix),
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"openshift_version": {
+				Description: "Version of OpenShift that the account roles will be " +
+					"used for, for example '4.12' or 'openshift-v4.12.5'.",
+				Type:     types.StringType,
+				Required: true,
+			},
+			"partition": {
+				Description: fmt.Sprintf("AWS partition used in the policy "+
+					"documents. Default is '%s'.", defaultAWSPartition),
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"redhat_aws_account_id": {
+				Description: "AWS account of Red Hat that the trust policies of the " +
+					"installer and support roles allow to assume them. Default is " +
+					"the account of the OCM environment of the provider, it must be " +
+					"set when using an environment that isn't known.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"account_roles": {
+				Description: "Account roles.",
+				Attributes: tfsdk.ListNestedAttributes(
+					t.itemAttributes(),
+					tfsdk.ListNestedAttributesOptions{},
+				),
+				Computed: true,
+			},
+		},
+	}
+	return
+}
+
+func (t *RosaAccountRolesDataSourceType) itemAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"role_type": {
+			Description: "Type of the role, one of 'installer', 'support', " +
+				"'instance_controlplane' or 'instance_worker'.",
+			Type:     types.StringType,
+			Computed: true,
+		},
+		"role_name": {
+			Description: "Name of the role.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"policy_name": {
+			Description: "Name of the permission policy of the role.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"trust_policy": {
+			Description: "Trust policy of the role, in JSON format.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"permission_policy": {
+			Description: "Permission policy of the role, in JSON format.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"tags": {
+			Description: "Tags that should be added to the role.",
+			Type: types.MapType{
+				ElemType: types.StringType,
+			},
+			Computed: true,
+		},
+	}
+}
+
+func (t *RosaAccountRolesDataSourceType) NewDataSource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.DataSource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation:
+	parent := p.(*Provider)
+
+	// Get the collection of STS policies:
+	stsPolicies := parent.connection.ClustersMgmt().V1().AWSInquiries().STSPolicies()
+
+	// Create the data source:
+	result = &RosaAccountRolesDataSource{
+		logger:      parent.logger,
+		connection:  parent.connection,
+		stsPolicies: stsPolicies,
+	}
+	return
+}
+
+func (s *RosaAccountRolesDataSource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateDataSourceConfigRequest, response *tfsdk.ValidateDataSourceConfigResponse) {
+	prefixPath := tftypes.NewAttributePath().WithAttributeName("account_role_prefix")
+	versionPath := tftypes.NewAttributePath().WithAttributeName("openshift_version")
+	accountIDPath := tftypes.NewAttributePath().WithAttributeName("redhat_aws_account_id")
+	var prefix, version, accountID types.String
+	diags := request.Config.GetAttribute(ctx, prefixPath, &prefix)
+	response.Diagnostics.Append(diags...)
+	diags = request.Config.GetAttribute(ctx, versionPath, &version)
+	response.Diagnostics.Append(diags...)
+	diags = request.Config.GetAttribute(ctx, accountIDPath, &accountID)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if isStringSet(accountID) && !awsAccountIDRE.MatchString(accountID.Value) {
+		response.Diagnostics.AddAttributeError(
+			accountIDPath,
+			"Invalid Red Hat AWS account",
+			fmt.Sprintf(
+				"Red Hat AWS account '%s' should contain 12 digits",
+				accountID.Value,
+			),
+		)
+	}
+
+	if isStringSet(prefix) {
+		if !accountRolePrefixRE.MatchString(prefix.Value) {
+			response.Diagnostics.AddAttributeError(
+				prefixPath,
+				"Invalid account role prefix",
+				fmt.Sprintf(
+					"Account role prefix '%s' can only contain alphanumeric "+
+						"characters and '+=,.@-_'",
+					prefix.Value,
+				),
+			)
+		} else if len(prefix.Value) > maxAccountRolePrefixLength() {
+			response.Diagnostics.AddAttributeError(
+				prefixPath,
+				"Invalid account role prefix",
+				fmt.Sprintf(
+					"Account role prefix '%s' is longer than %d characters",
+					prefix.Value, maxAccountRolePrefixLength(),
+				),
+			)
+		}
+	}
+	if isStringSet(version) {
+		supported, err := checkSupportedVersion(version.Value)
+		if err != nil {
+			response.Diagnostics.AddAttributeError(
+				versionPath,
+				"Invalid OpenShift version",
+				fmt.Sprintf("Version '%s' isn't a valid version: %v", version.Value, err),
+			)
+		} else if !supported {
+			response.Diagnostics.AddAttributeError(
+				versionPath,
+				"Invalid OpenShift version",
+				fmt.Sprintf(
+					"Version '%s' isn't supported, minimum supported version is %s",
+					version.Value, MinVersion,
+				),
+			)
+		}
+	}
+}
+
+func (s *RosaAccountRolesDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
+	response *tfsdk.ReadDataSourceResponse) {
+	// Get the configuration:
+	state := &RosaAccountRolesState{}
+	diags := request.Config.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	if !isStringSet(state.AccountRolePrefix) {
+		state.AccountRolePrefix = types.String{
+			Value: DefaultAccountRolePrefix,
+		}
+	}
+	if !isStringSet(state.Partition) {
+		state.Partition = types.String{
+			Value: defaultAWSPartition,
+		}
+	}
+	if !isStringSet(state.RedHatAWSAccountID) {
+		url := s.connection.URL()
+		accountID, ok := redHatAWSAccountID(url)
+		if !ok {
+			response.Diagnostics.AddError(
+				"Can't find Red Hat AWS account",
+				fmt.Sprintf(
+					"Can't find the Red Hat AWS account of the OCM environment '%s', "+
+						"set it explicitly with the 'redhat_aws_account_id' attribute",
+					url,
+				),
+			)
+			return
+		}
+		state.RedHatAWSAccountID = types.String{
+			Value: accountID,
+		}
+	}
+	version, err := parseVersion(state.OpenShiftVersion.Value)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't parse version",
+			fmt.Sprintf("Can't parse version '%s': %v", state.OpenShiftVersion.Value, err),
+		)
+		return
+	}
+	segments := version.Segments()
+	majorMinor := fmt.Sprintf("%d.%d", segments[0], segments[1])
+
+	// Fetch the policies:
+	policies, err := listStsPolicies(ctx, s.stsPolicies)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't list STS policies",
+			fmt.Sprintf("Can't list STS policies: %v", err),
+		)
+		return
+	}
+	values := map[string]string{
+		"partition":      state.Partition.Value,
+		"aws_account_id": state.RedHatAWSAccountID.Value,
+	}
+
+	// Populate the state:
+	prefix := state.AccountRolePrefix.Value
+	state.AccountRoles = make([]*AccountIAMRole, len(accountRoles))
+	for i, role := range accountRoles {
+		trustPolicy, err := stsPolicyDocument(policies, accountRoleTrustPolicyID(role), values)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't get trust policy",
+				fmt.Sprintf("Can't get trust policy of role '%s': %v", role.roleType, err),
+			)
+			return
+		}
+		permissionPolicy, err := stsPolicyDocument(policies, accountRolePermissionPolicyID(role), values)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't get permission policy",
+				fmt.Sprintf("Can't get permission policy of role '%s': %v", role.roleType, err),
+			)
+			return
+		}
+		state.AccountRoles[i] = &AccountIAMRole{
+			RoleType: types.String{
+				Value: role.roleType,
+			},
+			RoleName: types.String{
+				Value: accountRoleName(prefix, role),
+			},
+			PolicyName: types.String{
+				Value: accountRolePolicyName(prefix, role),
+			},
+			TrustPolicy: types.String{
+				Value: trustPolicy,
+			},
+			PermissionPolicy: types.String{
+				Value: permissionPolicy,
+			},
+			Tags: types.Map{
+				ElemType: types.StringType,
+				Elems: map[string]attr.Value{
+					redHatManagedTag:    types.String{Value: "true"},
+					roleOpenShiftVerTag: types.String{Value: majorMinor},
+					rolePrefixTag:       types.String{Value: prefix},
+					roleTypeTag:         types.String{Value: role.roleType},
+				},
+			},
+		}
+	}
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,38 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RosaAccountRolesState struct {
+	AccountRolePrefix  types.String      `tfsdk:"account_role_prefix"`
+	OpenShiftVersion   types.String      `tfsdk:"openshift_version"`
+	Partition          types.String      `tfsdk:"partition"`
+	RedHatAWSAccountID types.String      `tfsdk:"redhat_aws_account_id"`
+	AccountRoles       []*AccountIAMRole `tfsdk:"account_roles"`
+}
+
+type AccountIAMRole struct {
+	RoleType         types.String `tfsdk:"role_type"`
+	RoleName         types.String `tfsdk:"role_name"`
+	PolicyName       types.String `tfsdk:"policy_name"`
+	TrustPolicy      types.String `tfsdk:"trust_policy"`
+	PermissionPolicy types.String `tfsdk:"permission_policy"`
+	Tags             types.Map    `tfsdk:"tags"`
+}
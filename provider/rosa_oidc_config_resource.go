@@ -0,0 +1,341 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type RosaOidcConfigResourceType struct {
+}
+
+type RosaOidcConfigResource struct {
+	logger     logging.Logger
+	collection *cmv1.OidcConfigsClient
+	httpClient HttpClient
+}
+
+func (t *RosaOidcConfigResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "OIDC configuration that can be used by ROSA STS clusters instead of " +
+			"the one created for each cluster.",
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				Description: "Unique identifier of the OIDC configuration.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"managed": {
+				Description: "Indicates if the OIDC issuer is hosted by Red Hat. When false " +
+					"the issuer is hosted in the AWS account of the user, and 'secret_arn', " +
+					"'issuer_url' and 'installer_role_arn' are required.",
+				Type:     types.BoolType,
+				Required: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"secret_arn": {
+				Description: "ARN of the AWS Secrets Manager secret that contains the private " +
+					"key of the unmanaged OIDC configuration.",
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"issuer_url": {
+				Description: "URL of the OIDC issuer. Computed for managed OIDC configurations.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"installer_role_arn": {
+				Description: "ARN of the installer role used to create the unmanaged OIDC " +
+					"configuration.",
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"thumbprint": {
+				Description: "SHA1-hash value of the root CA of the issuer URL.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+	return
+}
+
+func (t *RosaOidcConfigResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collection of OIDC configurations:
+	collection := parent.connection.ClustersMgmt().V1().OidcConfigs()
+
+	// Create the resource:
+	result = &RosaOidcConfigResource{
+		logger:     parent.logger,
+		collection: collection,
+		httpClient: DefaultHttpClient{},
+	}
+
+	return
+}
+
+func (r *RosaOidcConfigResource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateResourceConfigRequest, response *tfsdk.ValidateResourceConfigResponse) {
+	var managed types.Bool
+	diags := request.Config.GetAttribute(ctx,
+		tftypes.NewAttributePath().WithAttributeName("managed"), &managed)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() || managed.Unknown || managed.Null {
+		return
+	}
+
+	// Managed configurations are created by Red Hat, so the attributes that describe the
+	// resources of the user can't be set, and unmanaged configurations need all of them:
+	for _, name := range []string{"secret_arn", "issuer_url", "installer_role_arn"} {
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		var value types.String
+		diags = request.Config.GetAttribute(ctx, path, &value)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+		if managed.Value && !value.Null {
+			response.Diagnostics.AddAttributeError(
+				path,
+				"Attribute not supported for managed OIDC configurations",
+				fmt.Sprintf(
+					"Attribute '%s' can only be set when 'managed' is false",
+					name,
+				),
+			)
+		}
+		if !managed.Value && value.Null {
+			response.Diagnostics.AddAttributeError(
+				path,
+				"Missing attribute for unmanaged OIDC configuration",
+				fmt.Sprintf(
+					"Attribute '%s' is required when 'managed' is false",
+					name,
+				),
+			)
+		}
+	}
+}
+
+func (r *RosaOidcConfigResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &RosaOidcConfigState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Create the OIDC configuration:
+	builder := cmv1.NewOidcConfig()
+	builder.Managed(state.Managed.Value)
+	if !state.Managed.Value {
+		builder.SecretArn(state.SecretARN.Value)
+		builder.IssuerUrl(state.IssuerURL.Value)
+		builder.InstallerRoleArn(state.InstallerRoleARN.Value)
+	}
+	object, err := builder.Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build OIDC configuration",
+			fmt.Sprintf("Can't build OIDC configuration: %v", err),
+		)
+		return
+	}
+	add, err := r.collection.Add().Body(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create OIDC configuration",
+			fmt.Sprintf("Can't create OIDC configuration: %v", err),
+		)
+		return
+	}
+	object = add.Body()
+
+	// Save the state:
+	r.populateState(ctx, object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *RosaOidcConfigResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &RosaOidcConfigState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Find the OIDC configuration, if it has been deleted outside of Terraform remove it from
+	// the state so that it is created again:
+	get, err := r.collection.OidcConfig(state.ID.Value).Get().SendContext(ctx)
+	if err != nil {
+		sdkErr, ok := err.(*errors.Error)
+		if ok && sdkErr.Status() == http.StatusNotFound {
+			r.logger.Warn(ctx, "OIDC configuration '%s' not found, removing from state",
+				state.ID.Value)
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find OIDC configuration",
+			fmt.Sprintf(
+				"Can't find OIDC configuration with identifier '%s': %v",
+				state.ID.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	r.populateState(ctx, object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *RosaOidcConfigResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	// All the attributes that can be set require replacing the OIDC configuration, so there is
+	// nothing to update.
+}
+
+func (r *RosaOidcConfigResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &RosaOidcConfigState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Send the request to delete the OIDC configuration:
+	_, err := r.collection.OidcConfig(state.ID.Value).Delete().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't delete OIDC configuration",
+			fmt.Sprintf(
+				"Can't delete OIDC configuration with identifier '%s': %v",
+				state.ID.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *RosaOidcConfigResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	// Try to retrieve the object:
+	get, err := r.collection.OidcConfig(request.ID).Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find OIDC configuration",
+			fmt.Sprintf(
+				"Can't find OIDC configuration with identifier '%s': %v",
+				request.ID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &RosaOidcConfigState{}
+	r.populateState(ctx, object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// populateState copies the data from the API object to the Terraform state.
+func (r *RosaOidcConfigResource) populateState(ctx context.Context, object *cmv1.OidcConfig,
+	state *RosaOidcConfigState) {
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	state.Managed = types.Bool{
+		Value: object.Managed(),
+	}
+	state.IssuerURL = types.String{
+		Value: object.IssuerUrl(),
+	}
+	state.SecretARN = types.String{
+		Null: true,
+	}
+	state.InstallerRoleARN = types.String{
+		Null: true,
+	}
+	if !object.Managed() {
+		secretARN, ok := object.GetSecretArn()
+		if ok {
+			state.SecretARN = types.String{
+				Value: secretARN,
+			}
+		}
+		installerRoleARN, ok := object.GetInstallerRoleArn()
+		if ok {
+			state.InstallerRoleARN = types.String{
+				Value: installerRoleARN,
+			}
+		}
+	}
+
+	// The thumbprint isn't returned by the API, so it is calculated from the certificates of the
+	// issuer:
+	if state.Thumbprint.Unknown || state.Thumbprint.Null || state.Thumbprint.Value == "" {
+		thumbprint, err := getThumbprint(object.IssuerUrl(), r.httpClient)
+		if err != nil {
+			r.logger.Error(ctx, "cannot get thumbprint: %v", err)
+		}
+		state.Thumbprint = types.String{
+			Value: thumbprint,
+		}
+	}
+}
@@ -18,14 +18,19 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"sort"
+	"strings"
 
+	semver "github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
 )
@@ -36,11 +41,14 @@ type RosaOperatorRolesDataSourceType struct {
 type RosaOperatorRolesDataSource struct {
 	logger       logging.Logger
 	awsInquiries *cmv1.AWSInquiriesClient
+	clusters     *cmv1.ClustersClient
 }
 
 const (
 	DefaultAccountRolePrefix = "ManagedOpenShift"
 	serviceAccountFmt        = "system:serviceaccount:%s:%s"
+	operatorTrustPolicyID    = "operator_iam_role_policy"
+	iamNameHashLength        = 8
 )
 
 func (t *RosaOperatorRolesDataSourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
@@ -48,15 +56,58 @@ func (t *RosaOperatorRolesDataSourceType) GetSchema(ctx context.Context) (result
 	result = tfsdk.Schema{
 		Description: "List of rosa operator role for a specific cluster.",
 		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of an existing cluster. When set the " +
+					"OpenShift version and the role prefixes are taken from the " +
+					"cluster, unless they are explicitly set.",
+				Type:     types.StringType,
+				Optional: true,
+			},
 			"operator_role_prefix": {
-				Description: "Operator role prefix.",
-				Type:        types.StringType,
-				Required:    true,
+				Description: "Operator role prefix. Required unless the " +
+					"'cluster' attribute is set.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
 			},
 			"account_role_prefix": {
 				Description: "Account role prefix.",
 				Type:        types.StringType,
 				Optional:    true,
+				Computed:    true,
+			},
+			"openshift_version": {
+				Description: "Version of OpenShift, for example '4.12' or " +
+					"'openshift-v4.12.5'. When set only the operators needed " +
+					"by that version are returned.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"oidc_endpoint_url": {
+				Description: "OIDC endpoint URL of the cluster, used to render the " +
+					"trust policies of the roles.",
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"aws_account_id": {
+				Description: "Identifier of the AWS account where the roles will be " +
+					"created, used to render the trust policies and the ARNs of " +
+					"the roles.",
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"partition": {
+				Description: fmt.Sprintf("AWS partition used in the policy "+
+					"documents and ARNs. Default is '%s'.", defaultAWSPartition),
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"path": {
+				Description: "Path of the IAM roles, for example '/rosa/'. Default " +
+					"is '/'.",
+				Type:     types.StringType,
+				Optional: true,
 			},
 			"operator_iam_roles": {
 				Description: "Operator IAM Roles.",
@@ -66,6 +117,15 @@ func (t *RosaOperatorRolesDataSourceType) GetSchema(ctx context.Context) (result
 				),
 				Computed: true,
 			},
+			"cluster_operator_iam_roles": {
+				Description: "Operator IAM roles as recorded in the cluster, only " +
+					"set when the 'cluster' attribute is set.",
+				Attributes: tfsdk.ListNestedAttributes(
+					t.clusterItemAttributes(),
+					tfsdk.ListNestedAttributesOptions{},
+				),
+				Computed: true,
+			},
 		},
 	}
 	return
@@ -100,6 +160,56 @@ func (t *RosaOperatorRolesDataSourceType) itemAttributes() map[string]tfsdk.Attr
 			},
 			Computed: true,
 		},
+		"role_arn": {
+			Description: "ARN of the role, only set when the AWS account " +
+				"identifier is given.",
+			Type:     types.StringType,
+			Computed: true,
+		},
+		"trust_policy": {
+			Description: "Trust policy of the role, in JSON format, only set " +
+				"when the OIDC endpoint URL and the AWS account identifier are given.",
+			Type:     types.StringType,
+			Computed: true,
+		},
+		"permission_policy": {
+			Description: "Permission policy of the role, in JSON format.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"attached": {
+			Description: "Indicates if the cluster has a role attached for " +
+				"this operator, only set when the 'cluster' attribute is set. " +
+				"When true the role name and ARN are the ones recorded in " +
+				"the cluster.",
+			Type:     types.BoolType,
+			Computed: true,
+		},
+	}
+}
+
+func (t *RosaOperatorRolesDataSourceType) clusterItemAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"operator_name": {
+			Description: "Operator Name",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"operator_namespace": {
+			Description: "Kubernetes Namespace",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"role_arn": {
+			Description: "ARN of the role.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"service_account": {
+			Description: "Service account that uses the role.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
 	}
 }
 
@@ -108,17 +218,81 @@ func (t *RosaOperatorRolesDataSourceType) NewDataSource(ctx context.Context,
 	// Cast the provider interface to the specific implementation:
 	parent := p.(*Provider)
 
-	// Get the collection of clusters:
+	// Get the AWS inquiries, used to get the credential requests and the policies:
 	awsInquiries := parent.connection.ClustersMgmt().V1().AWSInquiries()
 
+	// Get the collection of clusters:
+	clusters := parent.connection.ClustersMgmt().V1().Clusters()
+
 	// Create the resource:
 	result = &RosaOperatorRolesDataSource{
 		logger:       parent.logger,
 		awsInquiries: awsInquiries,
+		clusters:     clusters,
 	}
 	return
 }
 
+func (t *RosaOperatorRolesDataSource) ValidateConfig(ctx context.Context,
+	request tfsdk.ValidateDataSourceConfigRequest, response *tfsdk.ValidateDataSourceConfigResponse) {
+	clusterPath := tftypes.NewAttributePath().WithAttributeName("cluster")
+	prefixPath := tftypes.NewAttributePath().WithAttributeName("operator_role_prefix")
+	versionPath := tftypes.NewAttributePath().WithAttributeName("openshift_version")
+	accountIDPath := tftypes.NewAttributePath().WithAttributeName("aws_account_id")
+	pathPath := tftypes.NewAttributePath().WithAttributeName("path")
+	var cluster, prefix, version, accountID, path types.String
+	targets := map[*tftypes.AttributePath]interface{}{
+		clusterPath:   &cluster,
+		prefixPath:    &prefix,
+		versionPath:   &version,
+		accountIDPath: &accountID,
+		pathPath:      &path,
+	}
+	for attributePath, target := range targets {
+		diags := request.Config.GetAttribute(ctx, attributePath, target)
+		response.Diagnostics.Append(diags...)
+	}
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if cluster.Null && prefix.Null {
+		response.Diagnostics.AddAttributeError(
+			prefixPath,
+			"Missing operator role prefix",
+			"The operator role prefix is required when the 'cluster' attribute isn't set",
+		)
+	}
+	if isStringSet(version) {
+		_, err := parseVersion(version.Value)
+		if err != nil {
+			response.Diagnostics.AddAttributeError(
+				versionPath,
+				"Invalid OpenShift version",
+				fmt.Sprintf("Version '%s' isn't a valid version: %v", version.Value, err),
+			)
+		}
+	}
+
+	if isStringSet(accountID) && !awsAccountIDRE.MatchString(accountID.Value) {
+		response.Diagnostics.AddAttributeError(
+			accountIDPath,
+			"Invalid AWS account identifier",
+			fmt.Sprintf("AWS account identifier '%s' should contain 12 digits", accountID.Value),
+		)
+	}
+	if isStringSet(path) && !accountRolePathRE.MatchString(path.Value) {
+		response.Diagnostics.AddAttributeError(
+			pathPath,
+			"Invalid path",
+			fmt.Sprintf(
+				"Path '%s' should be '/' or start and end with '/'",
+				path.Value,
+			),
+		)
+	}
+}
+
 func (t *RosaOperatorRolesDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
 	response *tfsdk.ReadDataSourceResponse) {
 	// Get the state:
@@ -129,16 +303,117 @@ func (t *RosaOperatorRolesDataSource) Read(ctx context.Context, request tfsdk.Re
 		return
 	}
 
-	stsOperatorRolesList, err := t.awsInquiries.STSCredentialRequests().List().Send()
+	// Take the missing settings and the recorded roles from the cluster:
+	var clusterRoles map[string]*cmv1.OperatorIAMRole
+	if isStringSet(state.Cluster) {
+		get, err := t.clusters.Cluster(state.Cluster.Value).Get().SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't find cluster",
+				fmt.Sprintf(
+					"Can't find cluster with identifier '%s': %v",
+					state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		object := get.Body()
+		clusterRoles = map[string]*cmv1.OperatorIAMRole{}
+		for _, role := range object.AWS().STS().OperatorIAMRoles() {
+			clusterRoles[operatorKey(role.Namespace(), role.Name())] = role
+			state.ClusterIAMRoles = append(state.ClusterIAMRoles, &ClusterIAMRole{
+				Name: types.String{
+					Value: role.Name(),
+				},
+				Namespace: types.String{
+					Value: role.Namespace(),
+				},
+				RoleARN: types.String{
+					Value: role.RoleARN(),
+				},
+				ServiceAccount: types.String{
+					Value: role.ServiceAccount(),
+				},
+			})
+		}
+		if !isStringSet(state.OpenShiftVersion) {
+			state.OpenShiftVersion = types.String{
+				Value: object.Version().RawID(),
+			}
+			if state.OpenShiftVersion.Value == "" {
+				state.OpenShiftVersion.Value = object.Version().ID()
+			}
+		}
+		if !isStringSet(state.OperatorRolePrefix) {
+			state.OperatorRolePrefix = types.String{
+				Value: clusterOperatorRolePrefix(object),
+			}
+			if state.OperatorRolePrefix.Value == "" {
+				response.Diagnostics.AddError(
+					"Can't find operator role prefix",
+					fmt.Sprintf(
+						"Can't find the operator role prefix of cluster '%s', "+
+							"set the 'operator_role_prefix' attribute explicitly",
+						state.Cluster.Value,
+					),
+				)
+				return
+			}
+		}
+		if !isStringSet(state.AccountRolePrefix) {
+			prefix := clusterAccountRolePrefix(object)
+			if prefix != "" {
+				state.AccountRolePrefix = types.String{
+					Value: prefix,
+				}
+			}
+		}
+	}
+	var version *semver.Version
+	if isStringSet(state.OpenShiftVersion) {
+		var err error
+		version, err = parseVersion(state.OpenShiftVersion.Value)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't parse version",
+				fmt.Sprintf("Can't parse version '%s': %v", state.OpenShiftVersion.Value, err),
+			)
+			return
+		}
+	} else {
+		state.OpenShiftVersion = types.String{
+			Null: true,
+		}
+	}
+
+	stsOperatorRolesList, err := t.awsInquiries.STSCredentialRequests().List().SendContext(ctx)
 	if err != nil {
-		t.logger.Error(ctx, "Failed to get operator list")
+		response.Diagnostics.AddError(
+			"Can't list operator roles",
+			fmt.Sprintf("Can't list STS credential requests: %v", err),
+		)
 		return
 	}
 
-	stsOperatorMap := make(map[string]*cmv1.STSOperator)
+	stsOperatorMap := make(map[string]*cmv1.STSCredentialRequest)
 	roleNameSpaces := make([]string, 0)
 	stsOperatorRolesList.Items().Each(func(stsCredentialRequest *cmv1.STSCredentialRequest) bool {
-		// TODO: check the MinVersion of the operator role
+		if version != nil {
+			compatible, err := isOperatorCompatible(stsCredentialRequest.Operator(), version)
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Can't check operator version",
+					fmt.Sprintf("Can't check version of operator: %v", err),
+				)
+				return false
+			}
+			if !compatible {
+				t.logger.Debug(ctx, "Operator '%s' isn't needed by version '%s'",
+					stsCredentialRequest.Name(), state.OpenShiftVersion.Value,
+				)
+				return true
+			}
+		}
 		t.logger.Debug(ctx, "Operator name: %s, namespace %s, service account %s",
 			stsCredentialRequest.Operator().Name(),
 			stsCredentialRequest.Operator().Namespace(),
@@ -148,58 +423,218 @@ func (t *RosaOperatorRolesDataSource) Read(ctx context.Context, request tfsdk.Re
 		// the name of `ingress_operator_cloud_credentials` and `cloud_network_config_controller_cloud_credentials`
 		// both of them includes the same Name `cloud-credentials` and it cannot be fixed
 		roleNameSpaces = append(roleNameSpaces, stsCredentialRequest.Operator().Namespace())
-		stsOperatorMap[stsCredentialRequest.Operator().Namespace()] = stsCredentialRequest.Operator()
+		stsOperatorMap[stsCredentialRequest.Operator().Namespace()] = stsCredentialRequest
 		return true
 	})
+	if response.Diagnostics.HasError() {
+		return
+	}
 
-	accountRolePrefix := DefaultAccountRolePrefix
-	if !state.AccountRolePrefix.Unknown && !state.AccountRolePrefix.Null && state.AccountRolePrefix.Value != "" {
-		accountRolePrefix = state.AccountRolePrefix.Value
+	// Fetch the policies:
+	policies, err := listStsPolicies(ctx, t.awsInquiries.STSPolicies())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't list STS policies",
+			fmt.Sprintf("Can't list STS policies: %v", err),
+		)
+		return
 	}
 
-	// TODO: use the sts.OperatorRolePrefix() if not empty
-	// There is a bug in the return value of sts.OperatorRolePrefix() - it's always empty string
+	if !isStringSet(state.AccountRolePrefix) {
+		state.AccountRolePrefix = types.String{
+			Value: DefaultAccountRolePrefix,
+		}
+	}
+	accountRolePrefix := state.AccountRolePrefix.Value
+	partition := defaultAWSPartition
+	if isStringSet(state.Partition) {
+		partition = state.Partition.Value
+	}
+	values := map[string]string{
+		"partition": partition,
+	}
+	renderTrustPolicy := isStringSet(state.OIDCEndpointURL) && isStringSet(state.AWSAccountID)
+	if renderTrustPolicy {
+		issuerURL := strings.TrimPrefix(state.OIDCEndpointURL.Value, "https://")
+		values["issuer_url"] = issuerURL
+		values["oidc_provider_arn"] = fmt.Sprintf(
+			"arn:%s:iam::%s:oidc-provider/%s",
+			partition, state.AWSAccountID.Value, issuerURL,
+		)
+	}
+
+	// Calculate the names of the roles and policies of all the operators together, so that
+	// the ones that would collide after truncating them can be detected:
 	sort.Strings(roleNameSpaces)
-	for _, key := range roleNameSpaces {
+	roleNames := make([]string, len(roleNameSpaces))
+	policyNames := make([]string, len(roleNameSpaces))
+	for i, key := range roleNameSpaces {
+		operator := stsOperatorMap[key].Operator()
+		roleNames[i] = operatorIAMName(
+			state.OperatorRolePrefix.Value, operator.Namespace(), operator.Name(),
+		)
+		policyNames[i] = operatorIAMName(
+			accountRolePrefix, operator.Namespace(), operator.Name(),
+		)
+	}
+	roleNames = uniqueIAMNames(roleNames)
+	policyNames = uniqueIAMNames(policyNames)
+
+	var missing []string
+	for i, key := range roleNameSpaces {
+		credentialRequest := stsOperatorMap[key]
+		operator := credentialRequest.Operator()
+		roleName := roleNames[i]
 		r := OperatorIAMRole{
 			Name: types.String{
-				Value: stsOperatorMap[key].Name(),
+				Value: operator.Name(),
 			},
 			Namespace: types.String{
-				Value: stsOperatorMap[key].Namespace(),
+				Value: operator.Namespace(),
 			},
 			RoleName: types.String{
-				Value: getRoleName(state.OperatorRolePrefix.Value, stsOperatorMap[key]),
+				Value: roleName,
 			},
 			PolicyName: types.String{
-				Value: getPolicyName(accountRolePrefix, stsOperatorMap[key].Namespace(), stsOperatorMap[key].Name()),
+				Value: policyNames[i],
+			},
+			ServiceAccounts: buildServiceAccountsArray(operator.ServiceAccounts(), operator.Namespace()),
+			RoleARN: types.String{
+				Null: true,
 			},
-			ServiceAccounts: buildServiceAccountsArray(stsOperatorMap[stsOperatorMap[key].Namespace()].ServiceAccounts(), stsOperatorMap[key].Namespace()),
+			TrustPolicy: types.String{
+				Null: true,
+			},
+			Attached: types.Bool{
+				Null: true,
+			},
+		}
+		if isStringSet(state.AWSAccountID) {
+			r.RoleARN = types.String{
+				Value: roleARN(partition, state.AWSAccountID.Value, state.Path.Value, roleName),
+			}
+		}
+		if clusterRoles != nil {
+			clusterRole, ok := clusterRoles[operatorKey(operator.Namespace(), operator.Name())]
+			attached := ok && clusterRole.RoleARN() != ""
+			r.Attached = types.Bool{
+				Value: attached,
+			}
+			if attached {
+				r.RoleARN = types.String{
+					Value: clusterRole.RoleARN(),
+				}
+				r.RoleName = types.String{
+					Value: roleNameFromARN(clusterRole.RoleARN()),
+				}
+			} else {
+				missing = append(missing, operatorKey(operator.Namespace(), operator.Name()))
+			}
+		}
+		if renderTrustPolicy {
+			serviceAccounts := make([]string, len(operator.ServiceAccounts()))
+			for i, serviceAccount := range operator.ServiceAccounts() {
+				serviceAccounts[i] = fmt.Sprintf(serviceAccountFmt, operator.Namespace(), serviceAccount)
+			}
+			values["service_accounts"] = strings.Join(serviceAccounts, `" , "`)
+			trustPolicy, err := stsPolicyDocument(policies, operatorTrustPolicyID, values)
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Can't get trust policy",
+					fmt.Sprintf("Can't get trust policy of role '%s': %v", roleName, err),
+				)
+				return
+			}
+			r.TrustPolicy = types.String{
+				Value: trustPolicy,
+			}
+		}
+		permissionPolicy, err := stsPolicyDocument(
+			policies, operatorPermissionPolicyID(credentialRequest), values,
+		)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't get permission policy",
+				fmt.Sprintf("Can't get permission policy of role '%s': %v", roleName, err),
+			)
+			return
+		}
+		r.PermissionPolicy = types.String{
+			Value: permissionPolicy,
 		}
 		state.OperatorIAMRoles = append(state.OperatorIAMRoles, &r)
 	}
+	if len(missing) > 0 {
+		response.Diagnostics.AddWarning(
+			"Missing operator roles",
+			fmt.Sprintf(
+				"Cluster '%s' doesn't have a role attached for operators: %s",
+				state.Cluster.Value, strings.Join(missing, ", "),
+			),
+		)
+	}
 
 	// Save the state:
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
 }
 
-// TODO: should be in a separate repo
-func getRoleName(rolePrefix string, operatorRole *cmv1.STSOperator) string {
-	role := fmt.Sprintf("%s-%s-%s", rolePrefix, operatorRole.Namespace(), operatorRole.Name())
-	if len(role) > 64 {
-		role = role[0:64]
+// operatorIAMName returns the complete name of the role or policy of an operator, before
+// truncating it to the length limit of AWS.
+func operatorIAMName(prefix string, namespace string, name string) string {
+	return fmt.Sprintf("%s-%s-%s", prefix, namespace, name)
+}
+
+// operatorKey returns the key used to match the operators expected by OCM with the operator roles
+// recorded in a cluster, for example 'openshift-ingress-operator/cloud-credentials'.
+func operatorKey(namespace string, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// uniqueIAMNames returns the names of the roles or policies that correspond to the given complete
+// names. Names are truncated to the length limit of AWS, like the 'rosa' command line tool does.
+// When several names are truncated to the same value the end of those names is replaced with a
+// hash of the complete name, so that they don't collide. The rest of the names aren't changed.
+func uniqueIAMNames(names []string) []string {
+	counts := map[string]int{}
+	for _, name := range names {
+		counts[truncateIAMName(name)]++
 	}
-	return role
+	result := make([]string, len(names))
+	for i, name := range names {
+		result[i] = truncateIAMName(name)
+		if counts[result[i]] > 1 {
+			result[i] = hashIAMName(name)
+		}
+	}
+	return result
 }
 
-// TODO: should be in a separate repo
-func getPolicyName(prefix string, namespace string, name string) string {
-	policy := fmt.Sprintf("%s-%s-%s", prefix, namespace, name)
-	if len(policy) > 64 {
-		policy = policy[0:64]
+// truncateIAMName truncates the given name to the length limit of AWS for role and policy names.
+func truncateIAMName(name string) string {
+	if len(name) > maxRoleNameLength {
+		return name[:maxRoleNameLength]
 	}
-	return policy
+	return name
+}
+
+// hashIAMName returns a name that fits in the length limit of AWS for role and policy names,
+// replacing the end of the given name with a hash of the complete name.
+func hashIAMName(name string) string {
+	hash := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(hash[:])[:iamNameHashLength]
+	length := len(name)
+	if length > maxRoleNameLength-len(suffix)-1 {
+		length = maxRoleNameLength - len(suffix) - 1
+	}
+	return fmt.Sprintf("%s-%s", name[:length], suffix)
+}
+
+// operatorPermissionPolicyID returns the identifier of the STS policy that contains the permission
+// policy of the operator role for the given credential request, for example
+// 'openshift_ingress_operator_cloud_credentials_policy'.
+func operatorPermissionPolicyID(credentialRequest *cmv1.STSCredentialRequest) string {
+	return fmt.Sprintf("openshift_%s_policy", credentialRequest.Name())
 }
 
 func buildServiceAccountsArray(serviceAccountArr []string, operatorNamespace string) types.List {
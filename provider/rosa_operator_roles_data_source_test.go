@@ -0,0 +1,56 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Operator role names", func() {
+	It("Doesn't change names that fit", func() {
+		name := "my-cluster-openshift-ingress-operator-cloud-credentials"
+		Expect(uniqueIAMNames([]string{name})).To(Equal([]string{name}))
+	})
+
+	It("Truncates long names like the rosa command line tool", func() {
+		names := uniqueIAMNames([]string{
+			"ManagedOpenShift-openshift-cluster-csi-drivers-ebs-cloud-credentials",
+			"ManagedOpenShift-openshift-cloud-network-config-controller-cloud-credentials",
+		})
+		Expect(names).To(Equal([]string{
+			"ManagedOpenShift-openshift-cluster-csi-drivers-ebs-cloud-credent",
+			"ManagedOpenShift-openshift-cloud-network-config-controller-cloud",
+		}))
+	})
+
+	It("Only changes the names that collide after truncating them", func() {
+		prefix := strings.Repeat("x", maxRoleNameLength)
+		names := uniqueIAMNames([]string{
+			prefix + "-first",
+			prefix + "-second",
+			"my-cluster-openshift-ingress-operator-cloud-credentials",
+		})
+		Expect(names[0]).To(HaveLen(maxRoleNameLength))
+		Expect(names[1]).To(HaveLen(maxRoleNameLength))
+		Expect(names[0]).ToNot(Equal(names[1]))
+		Expect(names[0]).To(Equal(hashIAMName(prefix + "-first")))
+		Expect(names[2]).To(Equal("my-cluster-openshift-ingress-operator-cloud-credentials"))
+	})
+})
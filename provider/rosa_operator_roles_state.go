@@ -19,15 +19,33 @@ package provider
 import "github.com/hashicorp/terraform-plugin-framework/types"
 
 type RosaOperatorRolesState struct {
+	Cluster            types.String       `tfsdk:"cluster"`
+	OpenShiftVersion   types.String       `tfsdk:"openshift_version"`
 	OperatorRolePrefix types.String       `tfsdk:"operator_role_prefix"`
 	AccountRolePrefix  types.String       `tfsdk:"account_role_prefix"`
+	OIDCEndpointURL    types.String       `tfsdk:"oidc_endpoint_url"`
+	AWSAccountID       types.String       `tfsdk:"aws_account_id"`
+	Partition          types.String       `tfsdk:"partition"`
+	Path               types.String       `tfsdk:"path"`
 	OperatorIAMRoles   []*OperatorIAMRole `tfsdk:"operator_iam_roles"`
+	ClusterIAMRoles    []*ClusterIAMRole  `tfsdk:"cluster_operator_iam_roles"`
 }
 
 type OperatorIAMRole struct {
-	Name            types.String `tfsdk:"operator_name"`
-	Namespace       types.String `tfsdk:"operator_namespace"`
-	RoleName        types.String `tfsdk:"role_name"`
-	PolicyName      types.String `tfsdk:"policy_name"`
-	ServiceAccounts types.List   `tfsdk:"service_accounts"`
+	Name             types.String `tfsdk:"operator_name"`
+	Namespace        types.String `tfsdk:"operator_namespace"`
+	RoleName         types.String `tfsdk:"role_name"`
+	PolicyName       types.String `tfsdk:"policy_name"`
+	ServiceAccounts  types.List   `tfsdk:"service_accounts"`
+	RoleARN          types.String `tfsdk:"role_arn"`
+	TrustPolicy      types.String `tfsdk:"trust_policy"`
+	PermissionPolicy types.String `tfsdk:"permission_policy"`
+	Attached         types.Bool   `tfsdk:"attached"`
+}
+
+type ClusterIAMRole struct {
+	Name           types.String `tfsdk:"operator_name"`
+	Namespace      types.String `tfsdk:"operator_namespace"`
+	RoleARN        types.String `tfsdk:"role_arn"`
+	ServiceAccount types.String `tfsdk:"service_account"`
 }
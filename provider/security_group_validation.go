@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// securityGroupIDRE matches the identifiers of AWS security groups, which have eight or
+// seventeen hexadecimal digits.
+var securityGroupIDRE = regexp.MustCompile(`^sg-([0-9a-f]{8}|[0-9a-f]{17})$`)
+
+// securityGroupAttributes are the attributes of the ROSA cluster resource that contain
+// additional security groups.
+var securityGroupAttributes = []string{
+	"additional_control_plane_security_group_ids",
+	"additional_infra_security_group_ids",
+	"additional_compute_security_group_ids",
+}
+
+// ClusterSecurityGroupsConfig contains the attributes of the ROSA cluster resource that are
+// checked by the security group validation.
+type ClusterSecurityGroupsConfig struct {
+	SecurityGroupIDs map[string]types.List
+	AWSSubnetIDs     types.List
+}
+
+// readClusterSecurityGroupsConfig reads from the configuration the additional security groups
+// and the subnets of a cluster.
+func readClusterSecurityGroupsConfig(ctx context.Context,
+	config tfsdk.Config) (result *ClusterSecurityGroupsConfig, diags diag.Diagnostics) {
+	result = &ClusterSecurityGroupsConfig{
+		SecurityGroupIDs: map[string]types.List{},
+	}
+	for _, name := range securityGroupAttributes {
+		var value types.List
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		diags.Append(config.GetAttribute(ctx, path, &value)...)
+		result.SecurityGroupIDs[name] = value
+	}
+	path := tftypes.NewAttributePath().WithAttributeName("aws_subnet_ids")
+	diags.Append(config.GetAttribute(ctx, path, &result.AWSSubnetIDs)...)
+	return
+}
+
+// validateClusterSecurityGroups checks that the additional security groups are valid identifiers
+// without duplicates, and that they are only used when the cluster is installed in subnets
+// provided by the user, as the security groups belong to the VPC of those subnets. Values that
+// are unknown are ignored.
+func validateClusterSecurityGroups(config *ClusterSecurityGroupsConfig) (diags diag.Diagnostics) {
+	for _, name := range securityGroupAttributes {
+		list := config.SecurityGroupIDs[name]
+		if list.Null || list.Unknown {
+			continue
+		}
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		if config.AWSSubnetIDs.Null {
+			diags.AddAttributeError(
+				path,
+				"Security groups require subnets",
+				fmt.Sprintf(
+					"Attribute '%s' can only be used when the cluster is installed in "+
+						"existing subnets, set them with the 'aws_subnet_ids' attribute",
+					name,
+				),
+			)
+		}
+		for i, elem := range list.Elems {
+			value, ok := elem.(types.String)
+			if !ok || value.Unknown || value.Null {
+				continue
+			}
+			if !securityGroupIDRE.MatchString(value.Value) {
+				diags.AddAttributeError(
+					path.WithElementKeyInt(i),
+					"Invalid security group",
+					fmt.Sprintf(
+						"Value '%s' isn't a valid security group identifier, it should "+
+							"have the form 'sg-0123456789abcdef0'",
+						value.Value,
+					),
+				)
+			}
+		}
+		checkDuplicateElements(path, list, "security group", &diags)
+	}
+	return
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Security group validation", func() {
+	// newList returns a list of strings with the given values:
+	newList := func(values ...string) types.List {
+		result := types.List{
+			ElemType: types.StringType,
+			Elems:    make([]attr.Value, len(values)),
+		}
+		for i, value := range values {
+			result.Elems[i] = types.String{Value: value}
+		}
+		return result
+	}
+
+	// newConfig returns a configuration with subnets and without security groups:
+	newConfig := func() *ClusterSecurityGroupsConfig {
+		config := &ClusterSecurityGroupsConfig{
+			SecurityGroupIDs: map[string]types.List{},
+			AWSSubnetIDs:     newList("subnet-1", "subnet-2"),
+		}
+		for _, name := range securityGroupAttributes {
+			config.SecurityGroupIDs[name] = types.List{ElemType: types.StringType, Null: true}
+		}
+		return config
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	It("Accepts valid security groups", func() {
+		config := newConfig()
+		config.SecurityGroupIDs["additional_control_plane_security_group_ids"] = newList(
+			"sg-0123abcd",
+		)
+		config.SecurityGroupIDs["additional_compute_security_group_ids"] = newList(
+			"sg-0123456789abcdef0", "sg-0123abcd",
+		)
+		Expect(validateClusterSecurityGroups(config)).To(BeEmpty())
+	})
+
+	It("Ignores unknown values", func() {
+		config := newConfig()
+		config.SecurityGroupIDs["additional_infra_security_group_ids"] = types.List{
+			ElemType: types.StringType,
+			Unknown:  true,
+		}
+		config.SecurityGroupIDs["additional_compute_security_group_ids"] = types.List{
+			ElemType: types.StringType,
+			Elems:    []attr.Value{types.String{Unknown: true}},
+		}
+		Expect(validateClusterSecurityGroups(config)).To(BeEmpty())
+	})
+
+	It("Rejects security groups without subnets", func() {
+		config := newConfig()
+		config.AWSSubnetIDs = types.List{ElemType: types.StringType, Null: true}
+		config.SecurityGroupIDs["additional_infra_security_group_ids"] = newList("sg-0123abcd")
+		Expect(errorPaths(validateClusterSecurityGroups(config))).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("additional_infra_security_group_ids"),
+		))
+	})
+
+	It("Rejects invalid and duplicated identifiers", func() {
+		config := newConfig()
+		config.SecurityGroupIDs["additional_compute_security_group_ids"] = newList(
+			"sg-0123abcd", "0123abcd", "sg-0123ABCD", "sg-0123abcd",
+		)
+		path := tftypes.NewAttributePath().WithAttributeName("additional_compute_security_group_ids")
+		Expect(errorPaths(validateClusterSecurityGroups(config))).To(ConsistOf(
+			path.WithElementKeyInt(1),
+			path.WithElementKeyInt(2),
+			path.WithElementKeyInt(3),
+		))
+	})
+})
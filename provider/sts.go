@@ -13,6 +13,14 @@ func stsResource() tfsdk.NestedAttributes {
 			Optional:    true,
 			Computed:    true,
 		},
+		"oidc_config_id": {
+			Description: "Identifier of the OIDC configuration, created for example with the " +
+				"'ocm_rosa_oidc_config' resource. It is an alternative to " +
+				"'oidc_endpoint_url' and 'oidc_private_key_secret_arn'.",
+			Type:     types.StringType,
+			Optional: true,
+			Computed: true,
+		},
 		"oidc_private_key_secret_arn": {
 			Description: "OIDC Private Key Secret ARN",
 			Type:        types.StringType,
@@ -23,31 +31,49 @@ func stsResource() tfsdk.NestedAttributes {
 			Type:        types.StringType,
 			Computed:    true,
 		},
+		"account_role_prefix": {
+			Description: "Prefix of the account roles. When set, the ARNs of the roles that " +
+				"aren't given explicitly are derived from it, from 'path' and from " +
+				"'aws_account_id', for example '<prefix>-Installer-Role'.",
+			Type:     types.StringType,
+			Optional: true,
+		},
+		"path": {
+			Description: "Path of the account roles used to derive their ARNs. Default value " +
+				"is '/'.",
+			Type:     types.StringType,
+			Optional: true,
+		},
 		"role_arn": {
-			Description: "Installer Role",
+			Description: "Installer Role. Derived from 'account_role_prefix' if not set.",
 			Type:        types.StringType,
-			Required:    true,
+			Optional:    true,
+			Computed:    true,
 		},
 		"support_role_arn": {
-			Description: "Support Role",
+			Description: "Support Role. Derived from 'account_role_prefix' if not set.",
 			Type:        types.StringType,
-			Required:    true,
+			Optional:    true,
+			Computed:    true,
 		},
 		"instance_iam_roles": {
-			Description: "Instance IAM Roles",
+			Description: "Instance IAM Roles. Derived from 'account_role_prefix' if not set.",
 			Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
 				"master_role_arn": {
 					Description: "Master/Controller Plane Role ARN",
 					Type:        types.StringType,
-					Required:    true,
+					Optional:    true,
+					Computed:    true,
 				},
 				"worker_role_arn": {
 					Description: "Worker Node Role ARN",
 					Type:        types.StringType,
-					Required:    true,
+					Optional:    true,
+					Computed:    true,
 				},
 			}),
-			Required: true,
+			Optional: true,
+			Computed: true,
 		},
 		"operator_role_prefix": {
 			Description: "Operator IAM Role prefix",
@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+const (
+	stsCleanupReportSummary = "STS resources left in AWS"
+)
+
+// StsCleanupReport describes the AWS resources created for an STS cluster that aren't removed
+// when the cluster is deleted.
+type StsCleanupReport struct {
+	ClusterID        string   `json:"cluster_id"`
+	OperatorRoleARNs []string `json:"operator_role_arns"`
+	OIDCEndpointURL  string   `json:"oidc_endpoint_url"`
+	Thumbprint       string   `json:"thumbprint"`
+}
+
+// newStsCleanupReport builds the cleanup report from the cluster object as returned by the API
+// and the Terraform state. It returns nil if the cluster doesn't use STS.
+func newStsCleanupReport(object *cmv1.Cluster, state *ClusterRosaClassicState,
+	httpClient HttpClient) *StsCleanupReport {
+	if state.Sts == nil {
+		return nil
+	}
+	report := &StsCleanupReport{
+		ClusterID:        state.ID.Value,
+		OperatorRoleARNs: []string{},
+		Thumbprint:       state.Sts.Thumbprint.Value,
+	}
+	if !state.Sts.OIDCEndpointURL.Null && state.Sts.OIDCEndpointURL.Value != "" {
+		report.OIDCEndpointURL = "https://" + state.Sts.OIDCEndpointURL.Value
+	}
+
+	// Prefer the values recorded by the server, as the state may be outdated:
+	if object != nil {
+		sts, ok := object.AWS().GetSTS()
+		if ok {
+			for _, operatorRole := range sts.OperatorIAMRoles() {
+				roleARN, ok := operatorRole.GetRoleARN()
+				if ok && roleARN != "" {
+					report.OperatorRoleARNs = append(report.OperatorRoleARNs, roleARN)
+				}
+			}
+			oidcEndpointURL, ok := sts.GetOIDCEndpointURL()
+			if ok && oidcEndpointURL != "" {
+				report.OIDCEndpointURL = oidcEndpointURL
+			}
+		}
+	}
+
+	if report.Thumbprint == "" && report.OIDCEndpointURL != "" {
+		thumbprint, err := getThumbprint(report.OIDCEndpointURL, httpClient)
+		if err == nil {
+			report.Thumbprint = thumbprint
+		}
+	}
+	return report
+}
+
+// Detail returns the human readable description of the report, used in the warning diagnostic.
+func (r *StsCleanupReport) Detail() string {
+	operatorRoles := "none"
+	if len(r.OperatorRoleARNs) > 0 {
+		operatorRoles = "\n  - " + strings.Join(r.OperatorRoleARNs, "\n  - ")
+	}
+	return fmt.Sprintf(
+		"The cluster with identifier '%s' was deleted, but the following AWS resources "+
+			"weren't removed and should be deleted separately:\n"+
+			"Operator roles: %s\n"+
+			"OIDC endpoint URL: %s\n"+
+			"OIDC thumbprint: %s",
+		r.ClusterID, operatorRoles, r.OIDCEndpointURL, r.Thumbprint,
+	)
+}
+
+// WriteFile writes the report in JSON format to the given file.
+func (r *StsCleanupReport) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+const defaultAWSPartition = "aws"
+
+// stsPolicyPlaceholderRE matches the '%{name}' placeholders of the STS policy documents.
+var stsPolicyPlaceholderRE = regexp.MustCompile(`%\{[^}]*\}`)
+
+// redHatAWSAccountIDs contains the AWS accounts of Red Hat that the trust policies of the account
+// roles allow to assume them, indexed by the URL of the OCM API of each environment. These are
+// the same accounts used by the 'rosa' command line tool.
+var redHatAWSAccountIDs = map[string]string{
+	"https://api.openshift.com":             "710019948333",
+	"https://api.stage.openshift.com":       "644306948063",
+	"https://api.integration.openshift.com": "896164604406",
+	"http://localhost:8000":                 "765374464689",
+}
+
+// redHatAWSAccountID returns the AWS account of Red Hat that corresponds to the given OCM API
+// URL, and a flag indicating if the URL is one of the known environments.
+func redHatAWSAccountID(url string) (result string, ok bool) {
+	result, ok = redHatAWSAccountIDs[strings.TrimSuffix(url, "/")]
+	return
+}
+
+// listStsPolicies fetches from OCM the complete list of STS policies and returns them indexed by
+// identifier, for example 'sts_installer_permission_policy'.
+func listStsPolicies(ctx context.Context,
+	client *cmv1.AWSSTSPoliciesInquiryClient) (map[string]*cmv1.AWSSTSPolicy, error) {
+	result := map[string]*cmv1.AWSSTSPolicy{}
+	listSize := 100
+	listPage := 1
+	listRequest := client.List().Size(listSize)
+	for {
+		listResponse, err := listRequest.SendContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		listResponse.Items().Each(func(policy *cmv1.AWSSTSPolicy) bool {
+			result[policy.ID()] = policy
+			return true
+		})
+		if listResponse.Size() < listSize {
+			break
+		}
+		listPage++
+		listRequest.Page(listPage)
+	}
+	return result, nil
+}
+
+// stsPolicyDocument returns the document of the STS policy with the given identifier, replacing
+// the '%{name}' placeholders with the given values. It fails if any placeholder isn't replaced, as
+// the resulting document wouldn't be valid.
+func stsPolicyDocument(policies map[string]*cmv1.AWSSTSPolicy, id string,
+	values map[string]string) (string, error) {
+	policy, ok := policies[id]
+	if !ok || policy.Details() == "" {
+		return "", fmt.Errorf("policy '%s' doesn't exist", id)
+	}
+	document := policy.Details()
+	for name, value := range values {
+		document = strings.ReplaceAll(document, fmt.Sprintf("%%{%s}", name), value)
+	}
+	placeholder := stsPolicyPlaceholderRE.FindString(document)
+	if placeholder != "" {
+		return "", fmt.Errorf(
+			"policy '%s' contains placeholder '%s' that has no value",
+			id, placeholder,
+		)
+	}
+	return document, nil
+}
+
+// accountRoleTrustPolicyID returns the identifier of the STS policy that contains the trust policy
+// of the given account role.
+func accountRoleTrustPolicyID(role accountRole) string {
+	return fmt.Sprintf("sts_%s_trust_policy", role.roleType)
+}
+
+// accountRolePermissionPolicyID returns the identifier of the STS policy that contains the
+// permission policy of the given account role.
+func accountRolePermissionPolicyID(role accountRole) string {
+	return fmt.Sprintf("sts_%s_permission_policy", role.roleType)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("STS policies", func() {
+	var policies map[string]*cmv1.AWSSTSPolicy
+
+	BeforeEach(func() {
+		policy, err := cmv1.NewAWSSTSPolicy().
+			ID("sts_installer_permission_policy").
+			Details(`{"Resource": "arn:%{partition}:iam::%{aws_account_id}:role/*"}`).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		policies = map[string]*cmv1.AWSSTSPolicy{
+			policy.ID(): policy,
+		}
+	})
+
+	It("Replaces the placeholders", func() {
+		document, err := stsPolicyDocument(policies, "sts_installer_permission_policy", map[string]string{
+			"partition":      "aws-us-gov",
+			"aws_account_id": "123456789012",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(document).To(Equal(`{"Resource": "arn:aws-us-gov:iam::123456789012:role/*"}`))
+	})
+
+	It("Fails if a placeholder has no value", func() {
+		_, err := stsPolicyDocument(policies, "sts_installer_permission_policy", map[string]string{
+			"partition": "aws",
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("%{aws_account_id}"))
+	})
+
+	It("Fails if the policy doesn't exist", func() {
+		_, err := stsPolicyDocument(policies, "sts_support_permission_policy", nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Finds the Red Hat AWS account of the known environments", func() {
+		accountID, ok := redHatAWSAccountID("https://api.openshift.com")
+		Expect(ok).To(BeTrue())
+		Expect(accountID).To(Equal("710019948333"))
+		accountID, ok = redHatAWSAccountID("https://api.stage.openshift.com/")
+		Expect(ok).To(BeTrue())
+		Expect(accountID).To(Equal("644306948063"))
+	})
+
+	It("Doesn't find the Red Hat AWS account of unknown environments", func() {
+		_, ok := redHatAWSAccountID("https://my.example.com")
+		Expect(ok).To(BeFalse())
+	})
+})
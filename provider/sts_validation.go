@@ -0,0 +1,318 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var arnRE = regexp.MustCompile(
+	`^arn:(aws|aws-cn|aws-us-gov):([a-z0-9-]+):([a-z0-9-]*):(\d{12}):(.+)$`,
+)
+
+// awsARN contains the parts of an AWS resource name.
+type awsARN struct {
+	Partition string
+	Service   string
+	Region    string
+	AccountID string
+	Resource  string
+}
+
+// parseARN parses an AWS resource name like 'arn:aws:iam::123456789012:role/my-role'.
+func parseARN(value string) (result *awsARN, err error) {
+	matches := arnRE.FindStringSubmatch(value)
+	if matches == nil {
+		err = fmt.Errorf(
+			"'%s' isn't a valid ARN, it should have the form "+
+				"'arn:partition:service:region:account-id:resource'",
+			value,
+		)
+		return
+	}
+	result = &awsARN{
+		Partition: matches[1],
+		Service:   matches[2],
+		Region:    matches[3],
+		AccountID: matches[4],
+		Resource:  matches[5],
+	}
+	return
+}
+
+// awsPartition returns the AWS partition that contains the given region.
+func awsPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	default:
+		return "aws"
+	}
+}
+
+// clusterARNAttribute describes an attribute of the ROSA cluster resource that contains an ARN,
+// with the service and the prefix of the resource that it should have.
+type clusterARNAttribute struct {
+	path     *tftypes.AttributePath
+	service  string
+	resource string
+}
+
+// clusterARNAttributes are the attributes of the ROSA cluster resource that contain ARNs.
+var clusterARNAttributes = []clusterARNAttribute{
+	{
+		path:     tftypes.NewAttributePath().WithAttributeName("sts").WithAttributeName("role_arn"),
+		service:  "iam",
+		resource: "role/",
+	},
+	{
+		path:     tftypes.NewAttributePath().WithAttributeName("sts").WithAttributeName("support_role_arn"),
+		service:  "iam",
+		resource: "role/",
+	},
+	{
+		path: tftypes.NewAttributePath().WithAttributeName("sts").
+			WithAttributeName("instance_iam_roles").WithAttributeName("master_role_arn"),
+		service:  "iam",
+		resource: "role/",
+	},
+	{
+		path: tftypes.NewAttributePath().WithAttributeName("sts").
+			WithAttributeName("instance_iam_roles").WithAttributeName("worker_role_arn"),
+		service:  "iam",
+		resource: "role/",
+	},
+	{
+		path: tftypes.NewAttributePath().WithAttributeName("sts").
+			WithAttributeName("oidc_private_key_secret_arn"),
+		service:  "secretsmanager",
+		resource: "secret:",
+	},
+	{
+		path:     tftypes.NewAttributePath().WithAttributeName("kms_key_arn"),
+		service:  "kms",
+		resource: "key/",
+	},
+	{
+		path:     tftypes.NewAttributePath().WithAttributeName("audit_log_arn"),
+		service:  "iam",
+		resource: "role/",
+	},
+}
+
+// ClusterStsConfig contains the attributes of the ROSA cluster resource that are checked by the
+// STS validation.
+type ClusterStsConfig struct {
+	AWSAccountID            types.String
+	CloudRegion             types.String
+	Sts                     types.Object
+	OIDCEndpointURL         types.String
+	OIDCPrivateKeySecretArn types.String
+	OIDCConfigID            types.String
+	AccountRolePrefix       types.String
+	Path                    types.String
+	DisableSCPChecks        types.Bool
+	ARNs                    []types.String
+}
+
+// readClusterStsConfig reads from the configuration the attributes related to STS and to the AWS
+// resources used by the cluster. The values of the ARNs are in the same order than
+// clusterARNAttributes.
+func readClusterStsConfig(ctx context.Context, config tfsdk.Config) (result *ClusterStsConfig,
+	diags diag.Diagnostics) {
+	result = &ClusterStsConfig{
+		ARNs: make([]types.String, len(clusterARNAttributes)),
+	}
+	root := tftypes.NewAttributePath()
+	sts := root.WithAttributeName("sts")
+	targets := map[*tftypes.AttributePath]interface{}{
+		root.WithAttributeName("aws_account_id"):     &result.AWSAccountID,
+		root.WithAttributeName("cloud_region"):       &result.CloudRegion,
+		root.WithAttributeName("disable_scp_checks"): &result.DisableSCPChecks,
+		sts: &result.Sts,
+		sts.WithAttributeName("oidc_endpoint_url"):           &result.OIDCEndpointURL,
+		sts.WithAttributeName("oidc_private_key_secret_arn"): &result.OIDCPrivateKeySecretArn,
+		sts.WithAttributeName("oidc_config_id"):              &result.OIDCConfigID,
+		sts.WithAttributeName("account_role_prefix"):         &result.AccountRolePrefix,
+		sts.WithAttributeName("path"):                        &result.Path,
+	}
+	for i, attribute := range clusterARNAttributes {
+		targets[attribute.path] = &result.ARNs[i]
+	}
+	for path, target := range targets {
+		diags.Append(config.GetAttribute(ctx, path, target)...)
+	}
+	return
+}
+
+// validateClusterSts checks that the BYO OIDC attributes are consistent, that the ARNs are valid
+// and belong to the account and partition of the cluster, and that STS clusters don't use
+// attributes that are only supported for clusters that use AWS credentials.
+func validateClusterSts(config *ClusterStsConfig) (diags diag.Diagnostics) {
+	stsPath := tftypes.NewAttributePath().WithAttributeName("sts")
+
+	// Check that the BYO OIDC attributes are both set or both unset:
+	endpointSet := isStringSet(config.OIDCEndpointURL)
+	secretSet := isStringSet(config.OIDCPrivateKeySecretArn)
+	if !config.OIDCEndpointURL.Unknown && !config.OIDCPrivateKeySecretArn.Unknown &&
+		endpointSet != secretSet {
+		missing := "oidc_private_key_secret_arn"
+		present := "oidc_endpoint_url"
+		if secretSet {
+			missing, present = present, missing
+		}
+		diags.AddAttributeError(
+			stsPath.WithAttributeName(missing),
+			"Incomplete BYO OIDC configuration",
+			fmt.Sprintf(
+				"When using BYO OIDC both 'oidc_endpoint_url' and "+
+					"'oidc_private_key_secret_arn' should be set, but only '%s' is set",
+				present,
+			),
+		)
+	}
+
+	// The OIDC configuration identifier is an alternative to the BYO OIDC attributes:
+	if isStringSet(config.OIDCConfigID) && (endpointSet || secretSet) {
+		diags.AddAttributeError(
+			stsPath.WithAttributeName("oidc_config_id"),
+			"Conflicting OIDC configuration",
+			"Attribute 'oidc_config_id' can't be used together with 'oidc_endpoint_url' "+
+				"and 'oidc_private_key_secret_arn'",
+		)
+	}
+
+	// Check the account role prefix and path, and that the ARNs of the account roles are either
+	// given explicitly or can be derived from the prefix:
+	if isStringSet(config.AccountRolePrefix) {
+		prefix := config.AccountRolePrefix.Value
+		maxLength := maxAccountRolePrefixLength()
+		if !accountRolePrefixRE.MatchString(prefix) || len(prefix) > maxLength {
+			diags.AddAttributeError(
+				stsPath.WithAttributeName("account_role_prefix"),
+				"Invalid account role prefix",
+				fmt.Sprintf(
+					"Account role prefix '%s' isn't valid, it should contain only "+
+						"alphanumeric characters and '+=,.@-_', and be at most %d "+
+						"characters long",
+					prefix, maxLength,
+				),
+			)
+		}
+	}
+	if isStringSet(config.Path) && !accountRolePathRE.MatchString(config.Path.Value) {
+		diags.AddAttributeError(
+			stsPath.WithAttributeName("path"),
+			"Invalid path",
+			fmt.Sprintf(
+				"Path '%s' isn't valid, it should start and end with '/'",
+				config.Path.Value,
+			),
+		)
+	}
+	if !config.Sts.Null && !config.Sts.Unknown && config.AccountRolePrefix.Null {
+		for _, role := range accountRoles {
+			for i, attribute := range clusterARNAttributes {
+				if attribute.path.Equal(role.path) && config.ARNs[i].Null {
+					diags.AddAttributeError(
+						role.path,
+						"Missing account role",
+						"The ARN of the account role should be set explicitly "+
+							"unless 'account_role_prefix' is set",
+					)
+				}
+			}
+		}
+	}
+
+	// Check the ARNs:
+	for i, attribute := range clusterARNAttributes {
+		value := config.ARNs[i]
+		if !isStringSet(value) {
+			continue
+		}
+		arn, err := parseARN(value.Value)
+		if err != nil {
+			diags.AddAttributeError(attribute.path, "Invalid ARN", err.Error())
+			continue
+		}
+		if arn.Service != attribute.service || !strings.HasPrefix(arn.Resource, attribute.resource) {
+			diags.AddAttributeError(
+				attribute.path,
+				"Invalid ARN",
+				fmt.Sprintf(
+					"ARN '%s' should be for a resource of type '%s%s'",
+					value.Value, attribute.service, strings.TrimRight(attribute.resource, "/:"),
+				),
+			)
+			continue
+		}
+		if isStringSet(config.AWSAccountID) && arn.AccountID != config.AWSAccountID.Value {
+			diags.AddAttributeError(
+				attribute.path,
+				"ARN in a different AWS account",
+				fmt.Sprintf(
+					"ARN '%s' belongs to AWS account '%s', but the cluster is in "+
+						"AWS account '%s'",
+					value.Value, arn.AccountID, config.AWSAccountID.Value,
+				),
+			)
+		}
+		if isStringSet(config.CloudRegion) {
+			partition := awsPartition(config.CloudRegion.Value)
+			if arn.Partition != partition {
+				diags.AddAttributeError(
+					attribute.path,
+					"ARN in a different AWS partition",
+					fmt.Sprintf(
+						"ARN '%s' belongs to AWS partition '%s', but region '%s' is in "+
+							"partition '%s'",
+						value.Value, arn.Partition, config.CloudRegion.Value, partition,
+					),
+				)
+			}
+		}
+	}
+
+	// The checks of the service control policies are only performed for clusters that use AWS
+	// credentials, so they can't be disabled for STS clusters:
+	if !config.Sts.Null && !config.Sts.Unknown && !config.DisableSCPChecks.Unknown &&
+		config.DisableSCPChecks.Value {
+		diags.AddAttributeError(
+			tftypes.NewAttributePath().WithAttributeName("disable_scp_checks"),
+			"Attribute not supported for STS clusters",
+			"Attribute 'disable_scp_checks' can't be used with STS clusters, the service "+
+				"control policy checks are only performed for clusters that use AWS credentials",
+		)
+	}
+	return
+}
+
+// isStringSet checks if the given string value is known, not null and not empty.
+func isStringSet(value types.String) bool {
+	return !value.Unknown && !value.Null && value.Value != ""
+}
@@ -0,0 +1,242 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("STS validation", func() {
+	const (
+		accountID  = "123456789012"
+		installer  = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+		support    = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role"
+		controller = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role"
+		worker     = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+		secret     = "arn:aws:secretsmanager:us-east-1:123456789012:secret:oidc-u2u1-6GYVrU"
+	)
+
+	// arnIndex returns the position of the given attribute in the list of ARN attributes:
+	arnIndex := func(path *tftypes.AttributePath) int {
+		for i, attribute := range clusterARNAttributes {
+			if attribute.path.Equal(path) {
+				return i
+			}
+		}
+		Fail("Unknown ARN attribute " + path.String())
+		return -1
+	}
+
+	stsPath := tftypes.NewAttributePath().WithAttributeName("sts")
+	rolePath := stsPath.WithAttributeName("role_arn")
+	secretPath := stsPath.WithAttributeName("oidc_private_key_secret_arn")
+	endpointPath := stsPath.WithAttributeName("oidc_endpoint_url")
+	kmsPath := tftypes.NewAttributePath().WithAttributeName("kms_key_arn")
+	auditLogPath := tftypes.NewAttributePath().WithAttributeName("audit_log_arn")
+
+	// newConfig returns a valid configuration of an STS cluster:
+	newConfig := func() *ClusterStsConfig {
+		config := &ClusterStsConfig{
+			AWSAccountID: types.String{Value: accountID},
+			CloudRegion:  types.String{Value: "us-east-1"},
+			Sts: types.Object{
+				AttrTypes: map[string]attr.Type{},
+				Attrs:     map[string]attr.Value{},
+			},
+			OIDCEndpointURL:         types.String{Null: true},
+			OIDCPrivateKeySecretArn: types.String{Null: true},
+			OIDCConfigID:            types.String{Null: true},
+			AccountRolePrefix:       types.String{Null: true},
+			Path:                    types.String{Null: true},
+			DisableSCPChecks:        types.Bool{Null: true},
+			ARNs:                    make([]types.String, len(clusterARNAttributes)),
+		}
+		for i := range config.ARNs {
+			config.ARNs[i] = types.String{Null: true}
+		}
+		config.ARNs[arnIndex(rolePath)] = types.String{Value: installer}
+		config.ARNs[arnIndex(stsPath.WithAttributeName("support_role_arn"))] = types.String{
+			Value: support,
+		}
+		instanceRolesPath := stsPath.WithAttributeName("instance_iam_roles")
+		config.ARNs[arnIndex(instanceRolesPath.WithAttributeName("master_role_arn"))] = types.String{
+			Value: controller,
+		}
+		config.ARNs[arnIndex(instanceRolesPath.WithAttributeName("worker_role_arn"))] = types.String{
+			Value: worker,
+		}
+		return config
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	It("Parses ARNs", func() {
+		arn, err := parseARN(installer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(arn.Partition).To(Equal("aws"))
+		Expect(arn.Service).To(Equal("iam"))
+		Expect(arn.Region).To(BeEmpty())
+		Expect(arn.AccountID).To(Equal(accountID))
+		Expect(arn.Resource).To(Equal("role/ManagedOpenShift-Installer-Role"))
+		_, err = parseARN("arn:aws:iam::account-id:role/my-role")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Finds the partition of the region", func() {
+		Expect(awsPartition("us-east-1")).To(Equal("aws"))
+		Expect(awsPartition("us-gov-west-1")).To(Equal("aws-us-gov"))
+		Expect(awsPartition("cn-north-1")).To(Equal("aws-cn"))
+	})
+
+	It("Accepts a valid configuration", func() {
+		Expect(validateClusterSts(newConfig())).To(BeEmpty())
+	})
+
+	It("Accepts a complete BYO OIDC configuration", func() {
+		config := newConfig()
+		config.OIDCEndpointURL = types.String{Value: "oidc.example.com/123"}
+		config.OIDCPrivateKeySecretArn = types.String{Value: secret}
+		config.ARNs[arnIndex(secretPath)] = config.OIDCPrivateKeySecretArn
+		Expect(validateClusterSts(config)).To(BeEmpty())
+	})
+
+	It("Rejects an endpoint without secret", func() {
+		config := newConfig()
+		config.OIDCEndpointURL = types.String{Value: "oidc.example.com/123"}
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(secretPath))
+	})
+
+	It("Rejects a secret without endpoint", func() {
+		config := newConfig()
+		config.OIDCPrivateKeySecretArn = types.String{Value: secret}
+		config.ARNs[arnIndex(secretPath)] = config.OIDCPrivateKeySecretArn
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(endpointPath))
+	})
+
+	It("Rejects an OIDC configuration identifier together with the BYO OIDC attributes", func() {
+		config := newConfig()
+		config.OIDCConfigID = types.String{Value: "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"}
+		Expect(validateClusterSts(config)).To(BeEmpty())
+		config.OIDCEndpointURL = types.String{Value: "oidc.example.com/123"}
+		config.OIDCPrivateKeySecretArn = types.String{Value: secret}
+		config.ARNs[arnIndex(secretPath)] = config.OIDCPrivateKeySecretArn
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(
+			stsPath.WithAttributeName("oidc_config_id"),
+		))
+	})
+
+	It("Rejects ARNs with invalid syntax", func() {
+		config := newConfig()
+		config.ARNs[arnIndex(rolePath)] = types.String{Value: "ManagedOpenShift-Installer-Role"}
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(rolePath))
+	})
+
+	It("Rejects ARNs of the wrong resource type", func() {
+		config := newConfig()
+		config.ARNs[arnIndex(rolePath)] = types.String{
+			Value: "arn:aws:iam::123456789012:user/my-user",
+		}
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(rolePath))
+	})
+
+	It("Rejects audit log ARNs that aren't roles", func() {
+		config := newConfig()
+		config.ARNs[arnIndex(auditLogPath)] = types.String{
+			Value: "arn:aws:logs:us-east-1:123456789012:log-group:my-group",
+		}
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(auditLogPath))
+		config.ARNs[arnIndex(auditLogPath)] = types.String{
+			Value: "arn:aws:iam::123456789012:role/my-audit-log-role",
+		}
+		Expect(validateClusterSts(config)).To(BeEmpty())
+	})
+
+	It("Rejects ARNs from other accounts", func() {
+		config := newConfig()
+		config.ARNs[arnIndex(kmsPath)] = types.String{
+			Value: "arn:aws:kms:us-east-1:210987654321:key/mrk-0123456789abcdef0123456789abcdef",
+		}
+		diags := validateClusterSts(config)
+		Expect(errorPaths(diags)).To(ConsistOf(kmsPath))
+		Expect(diags[0].Detail()).To(ContainSubstring("210987654321"))
+	})
+
+	It("Rejects ARNs from other partitions", func() {
+		config := newConfig()
+		config.CloudRegion = types.String{Value: "us-gov-west-1"}
+		diags := validateClusterSts(config)
+		Expect(diags).To(HaveLen(4))
+		Expect(diags[0].Detail()).To(ContainSubstring("aws-us-gov"))
+	})
+
+	It("Ignores unknown values", func() {
+		config := newConfig()
+		config.AWSAccountID = types.String{Unknown: true}
+		config.ARNs[arnIndex(rolePath)] = types.String{Unknown: true}
+		config.OIDCEndpointURL = types.String{Unknown: true}
+		config.OIDCPrivateKeySecretArn = types.String{Value: secret}
+		Expect(validateClusterSts(config)).To(BeEmpty())
+	})
+
+	It("Requires the account roles unless the prefix is set", func() {
+		config := newConfig()
+		config.ARNs[arnIndex(rolePath)] = types.String{Null: true}
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(rolePath))
+		config.AccountRolePrefix = types.String{Value: "ManagedOpenShift"}
+		Expect(validateClusterSts(config)).To(BeEmpty())
+	})
+
+	It("Checks the account role prefix and path", func() {
+		config := newConfig()
+		config.AccountRolePrefix = types.String{Value: "my prefix"}
+		config.Path = types.String{Value: "my-path"}
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(
+			stsPath.WithAttributeName("account_role_prefix"),
+			stsPath.WithAttributeName("path"),
+		))
+		config.AccountRolePrefix = types.String{Value: "my-prefix"}
+		config.Path = types.String{Value: "/my/path/"}
+		Expect(validateClusterSts(config)).To(BeEmpty())
+	})
+
+	It("Rejects disabling the SCP checks in STS clusters", func() {
+		config := newConfig()
+		config.DisableSCPChecks = types.Bool{Value: true}
+		Expect(errorPaths(validateClusterSts(config))).To(ConsistOf(
+			tftypes.NewAttributePath().WithAttributeName("disable_scp_checks"),
+		))
+		config.Sts = types.Object{Null: true}
+		for i := range config.ARNs {
+			config.ARNs[i] = types.String{Null: true}
+		}
+		Expect(validateClusterSts(config)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,320 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// These are the number of availability zones and the minimum number of compute nodes that OCM
+// accepts for single and multiple availability zone clusters.
+const (
+	singleAZCount       = 1
+	multiAZCount        = 3
+	singleAZMinReplicas = 2
+	multiAZMinReplicas  = 3
+)
+
+// ClusterTopologyConfig contains the attributes of the ROSA cluster resource that are checked by
+// the topology validation.
+type ClusterTopologyConfig struct {
+	MultiAZ            types.Bool
+	AvailabilityZones  types.List
+	AWSSubnetIDs       types.List
+	AWSPrivateLink     types.Bool
+	AutoScalingEnabled types.Bool
+	Replicas           types.Int64
+	MinReplicas        types.Int64
+	MaxReplicas        types.Int64
+}
+
+// readClusterTopologyConfig reads from the configuration the attributes that describe the
+// availability zones, subnets and compute nodes of a cluster.
+func readClusterTopologyConfig(ctx context.Context, config tfsdk.Config) (result *ClusterTopologyConfig,
+	diags diag.Diagnostics) {
+	result = &ClusterTopologyConfig{}
+	targets := map[string]interface{}{
+		"multi_az":            &result.MultiAZ,
+		"availability_zones":  &result.AvailabilityZones,
+		"aws_subnet_ids":      &result.AWSSubnetIDs,
+		"aws_private_link":    &result.AWSPrivateLink,
+		"autoscaling_enabled": &result.AutoScalingEnabled,
+		"replicas":            &result.Replicas,
+		"min_replicas":        &result.MinReplicas,
+		"max_replicas":        &result.MaxReplicas,
+	}
+	for name, target := range targets {
+		path := tftypes.NewAttributePath().WithAttributeName(name)
+		diags.Append(config.GetAttribute(ctx, path, target)...)
+	}
+	return
+}
+
+// ComputeNodes returns the maximum number of compute nodes that the cluster can have, which is
+// the maximum number of replicas when autoscaling is enabled and the number of replicas otherwise.
+func (c *ClusterTopologyConfig) ComputeNodes() types.Int64 {
+	switch {
+	case c.AutoScalingEnabled.Unknown:
+		return types.Int64{
+			Unknown: true,
+		}
+	case c.AutoScalingEnabled.Value:
+		return c.MaxReplicas
+	default:
+		return c.Replicas
+	}
+}
+
+// validateClusterTopology checks that the number of availability zones, subnets and compute nodes
+// are consistent with the 'multi_az' and 'aws_private_link' attributes. Values that are unknown
+// are ignored, and null boolean values are treated as false, like OCM does.
+func validateClusterTopology(config *ClusterTopologyConfig) (diags diag.Diagnostics) {
+	// Everything else depends on the number of availability zones:
+	if config.MultiAZ.Unknown {
+		return
+	}
+	multiAZ := config.MultiAZ.Value
+	zones := singleAZCount
+	if multiAZ {
+		zones = multiAZCount
+	}
+
+	// Check the availability zones:
+	if !config.AvailabilityZones.Unknown && !config.AvailabilityZones.Null {
+		path := tftypes.NewAttributePath().WithAttributeName("availability_zones")
+		if len(config.AvailabilityZones.Elems) != zones {
+			diags.AddAttributeError(
+				path,
+				"Invalid number of availability zones",
+				fmt.Sprintf(
+					"%s clusters need exactly %d availability zone(s), but %d were given",
+					topologyName(multiAZ), zones, len(config.AvailabilityZones.Elems),
+				),
+			)
+		}
+		checkDuplicateElements(path, config.AvailabilityZones, "availability zone", &diags)
+	}
+
+	// Check the subnets, PrivateLink clusters need one private subnet per availability zone and
+	// other clusters need one public and one private subnet per availability zone:
+	if !config.AWSSubnetIDs.Unknown && !config.AWSSubnetIDs.Null && !config.AWSPrivateLink.Unknown {
+		path := tftypes.NewAttributePath().WithAttributeName("aws_subnet_ids")
+		required := 2 * zones
+		detail := "%s clusters without PrivateLink need a public and a private subnet in each " +
+			"availability zone, so exactly %d subnets, but %d were given"
+		if config.AWSPrivateLink.Value {
+			required = zones
+			detail = "%s PrivateLink clusters need a private subnet in each availability zone, " +
+				"so exactly %d subnets, but %d were given"
+		}
+		if len(config.AWSSubnetIDs.Elems) != required {
+			diags.AddAttributeError(
+				path,
+				"Invalid number of subnets",
+				fmt.Sprintf(detail, topologyName(multiAZ), required, len(config.AWSSubnetIDs.Elems)),
+			)
+		}
+		checkDuplicateElements(path, config.AWSSubnetIDs, "subnet", &diags)
+	}
+
+	// Check the compute nodes, the number of replicas is incompatible with autoscaling and the
+	// limits of autoscaling are required when it is enabled:
+	if config.AutoScalingEnabled.Unknown {
+		return
+	}
+	if !config.AutoScalingEnabled.Value {
+		for _, limit := range autoscalingLimits(config) {
+			name, value := limit.name, limit.value
+			if !value.Null && !value.Unknown {
+				diags.AddAttributeError(
+					tftypes.NewAttributePath().WithAttributeName(name),
+					"Autoscaling isn't enabled",
+					fmt.Sprintf(
+						"Attribute '%s' can only be used when 'autoscaling_enabled' is true",
+						name,
+					),
+				)
+			}
+		}
+		checkReplicas("replicas", config.Replicas, multiAZ, &diags)
+		return
+	}
+	if !config.Replicas.Null && !config.Replicas.Unknown {
+		diags.AddAttributeError(
+			tftypes.NewAttributePath().WithAttributeName("replicas"),
+			"Autoscaling is enabled",
+			"Attribute 'replicas' can't be used when 'autoscaling_enabled' is true, use "+
+				"'min_replicas' and 'max_replicas' instead",
+		)
+	}
+	for _, limit := range autoscalingLimits(config) {
+		name, value := limit.name, limit.value
+		if value.Null {
+			diags.AddAttributeError(
+				tftypes.NewAttributePath().WithAttributeName(name),
+				"Missing autoscaling limit",
+				fmt.Sprintf(
+					"Attribute '%s' is required when 'autoscaling_enabled' is true",
+					name,
+				),
+			)
+		}
+	}
+	minOK := checkReplicas("min_replicas", config.MinReplicas, multiAZ, &diags)
+	maxOK := checkReplicas("max_replicas", config.MaxReplicas, multiAZ, &diags)
+	if minOK && maxOK &&
+		!config.MinReplicas.Null && !config.MinReplicas.Unknown &&
+		!config.MaxReplicas.Null && !config.MaxReplicas.Unknown &&
+		config.MinReplicas.Value > config.MaxReplicas.Value {
+		diags.AddAttributeError(
+			tftypes.NewAttributePath().WithAttributeName("max_replicas"),
+			"Invalid number of replicas",
+			fmt.Sprintf(
+				"Value of 'max_replicas' (%d) should be greater than or equal to the value "+
+					"of 'min_replicas' (%d)",
+				config.MaxReplicas.Value, config.MinReplicas.Value,
+			),
+		)
+	}
+	return
+}
+
+// modifyPlanComputeNodes checks the changes of the compute nodes that depend on the current state
+// of the cluster. Disabling autoscaling requires an explicit number of replicas, otherwise the
+// default machine pool would keep whatever number of nodes the autoscaler left.
+func modifyPlanComputeNodes(ctx context.Context, request tfsdk.ModifyResourcePlanRequest,
+	response *tfsdk.ModifyResourcePlanResponse) {
+	// Nothing to do when the resource is being created or destroyed:
+	if request.State.Raw.IsNull() || request.Plan.Raw.IsNull() {
+		return
+	}
+
+	autoscalingPath := tftypes.NewAttributePath().WithAttributeName("autoscaling_enabled")
+	replicasPath := tftypes.NewAttributePath().WithAttributeName("replicas")
+	var wasAutoscaling, autoscaling types.Bool
+	var replicas types.Int64
+	diags := request.State.GetAttribute(ctx, autoscalingPath, &wasAutoscaling)
+	response.Diagnostics.Append(diags...)
+	diags = request.Config.GetAttribute(ctx, autoscalingPath, &autoscaling)
+	response.Diagnostics.Append(diags...)
+	diags = request.Config.GetAttribute(ctx, replicasPath, &replicas)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	if wasAutoscaling.Null || !wasAutoscaling.Value || autoscaling.Unknown || autoscaling.Value {
+		return
+	}
+	if replicas.Null {
+		response.Diagnostics.AddAttributeError(
+			replicasPath,
+			"Missing number of replicas",
+			"Attribute 'replicas' is required when disabling autoscaling of the default "+
+				"machine pool",
+		)
+	}
+}
+
+// autoscalingLimit is the name and value of one of the autoscaling limits of the cluster.
+type autoscalingLimit struct {
+	name  string
+	value types.Int64
+}
+
+// autoscalingLimits returns the autoscaling limits of the cluster, in a fixed order so that the
+// diagnostics are always reported in the same order.
+func autoscalingLimits(config *ClusterTopologyConfig) []autoscalingLimit {
+	return []autoscalingLimit{
+		{name: "min_replicas", value: config.MinReplicas},
+		{name: "max_replicas", value: config.MaxReplicas},
+	}
+}
+
+// checkReplicas checks that the given number of replicas is valid for the topology of the cluster.
+// Multiple availability zone clusters need at least three replicas, and a multiple of three so
+// that they are distributed evenly. Single availability zone clusters need at least two. It
+// returns false if the value isn't valid, and in that case it also adds the error to the
+// diagnostics.
+func checkReplicas(name string, value types.Int64, multiAZ bool, diags *diag.Diagnostics) bool {
+	if value.Unknown || value.Null {
+		return true
+	}
+	path := tftypes.NewAttributePath().WithAttributeName(name)
+	if multiAZ {
+		if value.Value < multiAZMinReplicas || value.Value%multiAZCount != 0 {
+			diags.AddAttributeError(
+				path,
+				"Invalid number of replicas",
+				fmt.Sprintf(
+					"Multiple availability zone clusters need at least %d replicas and a "+
+						"multiple of %d, but '%s' is %d",
+					multiAZMinReplicas, multiAZCount, name, value.Value,
+				),
+			)
+			return false
+		}
+		return true
+	}
+	if value.Value < singleAZMinReplicas {
+		diags.AddAttributeError(
+			path,
+			"Invalid number of replicas",
+			fmt.Sprintf(
+				"Single availability zone clusters need at least %d replicas, but '%s' is %d",
+				singleAZMinReplicas, name, value.Value,
+			),
+		)
+		return false
+	}
+	return true
+}
+
+// checkDuplicateElements reports the elements of a list of strings that appear more than once,
+// using the path of the repeated element.
+func checkDuplicateElements(path *tftypes.AttributePath, list types.List, kind string,
+	diags *diag.Diagnostics) {
+	seen := map[string]bool{}
+	for i, element := range list.Elems {
+		value, ok := element.(types.String)
+		if !ok || value.Unknown || value.Null {
+			continue
+		}
+		if seen[value.Value] {
+			diags.AddAttributeError(
+				path.WithElementKeyInt(i),
+				fmt.Sprintf("Duplicated %s", kind),
+				fmt.Sprintf("The %s '%s' appears more than once", kind, value.Value),
+			)
+			continue
+		}
+		seen[value.Value] = true
+	}
+}
+
+// topologyName returns the name of the topology used in error messages.
+func topologyName(multiAZ bool) string {
+	if multiAZ {
+		return "Multiple availability zone"
+	}
+	return "Single availability zone"
+}
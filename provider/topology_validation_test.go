@@ -0,0 +1,228 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Topology validation", func() {
+	// newConfig returns a configuration where all the attributes are null:
+	newConfig := func() *ClusterTopologyConfig {
+		return &ClusterTopologyConfig{
+			MultiAZ:            types.Bool{Null: true},
+			AvailabilityZones:  types.List{ElemType: types.StringType, Null: true},
+			AWSSubnetIDs:       types.List{ElemType: types.StringType, Null: true},
+			AWSPrivateLink:     types.Bool{Null: true},
+			AutoScalingEnabled: types.Bool{Null: true},
+			Replicas:           types.Int64{Null: true},
+			MinReplicas:        types.Int64{Null: true},
+			MaxReplicas:        types.Int64{Null: true},
+		}
+	}
+
+	// newList returns a list of strings with the given values:
+	newList := func(values ...string) types.List {
+		result := types.List{
+			ElemType: types.StringType,
+			Elems:    make([]attr.Value, len(values)),
+		}
+		for i, value := range values {
+			result.Elems[i] = types.String{Value: value}
+		}
+		return result
+	}
+
+	// errorPaths returns the attribute paths of the error diagnostics:
+	errorPaths := func(diags diag.Diagnostics) []*tftypes.AttributePath {
+		var result []*tftypes.AttributePath
+		for _, d := range diags {
+			if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+				result = append(result, withPath.Path())
+			}
+		}
+		return result
+	}
+
+	attributePath := func(name string) *tftypes.AttributePath {
+		return tftypes.NewAttributePath().WithAttributeName(name)
+	}
+
+	It("Accepts an empty configuration", func() {
+		Expect(validateClusterTopology(newConfig())).To(BeEmpty())
+	})
+
+	It("Accepts a valid multiple availability zone PrivateLink cluster", func() {
+		config := newConfig()
+		config.MultiAZ = types.Bool{Value: true}
+		config.AvailabilityZones = newList("az1", "az2", "az3")
+		config.AWSPrivateLink = types.Bool{Value: true}
+		config.AWSSubnetIDs = newList("id1", "id2", "id3")
+		config.Replicas = types.Int64{Value: 6}
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+	})
+
+	It("Ignores everything when 'multi_az' is unknown", func() {
+		config := newConfig()
+		config.MultiAZ = types.Bool{Unknown: true}
+		config.AvailabilityZones = newList("az1", "az2")
+		config.Replicas = types.Int64{Value: 1}
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+	})
+
+	It("Checks the number of availability zones", func() {
+		config := newConfig()
+		config.AvailabilityZones = newList("az1", "az2", "az3")
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("availability_zones"),
+		))
+		config.MultiAZ = types.Bool{Value: true}
+		config.AvailabilityZones = newList("az1")
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("availability_zones"),
+		))
+	})
+
+	It("Reports duplicated availability zones in the repeated element", func() {
+		config := newConfig()
+		config.MultiAZ = types.Bool{Value: true}
+		config.AvailabilityZones = newList("az1", "az2", "az1")
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("availability_zones").WithElementKeyInt(2),
+		))
+	})
+
+	It("Needs a public and a private subnet per zone without PrivateLink", func() {
+		config := newConfig()
+		config.AWSSubnetIDs = newList("id1", "id2")
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+		config.AWSSubnetIDs = newList("id1")
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("aws_subnet_ids"),
+		))
+		config.MultiAZ = types.Bool{Value: true}
+		config.AWSSubnetIDs = newList("id1", "id2", "id3")
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("aws_subnet_ids"),
+		))
+	})
+
+	It("Needs a private subnet per zone with PrivateLink", func() {
+		config := newConfig()
+		config.AWSPrivateLink = types.Bool{Value: true}
+		config.AWSSubnetIDs = newList("id1")
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+		config.AWSSubnetIDs = newList("id1", "id2")
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("aws_subnet_ids"),
+		))
+	})
+
+	It("Checks the replicas of single availability zone clusters", func() {
+		config := newConfig()
+		config.Replicas = types.Int64{Value: 2}
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+		config.Replicas = types.Int64{Value: 1}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("replicas"),
+		))
+	})
+
+	It("Checks the replicas of multiple availability zone clusters", func() {
+		config := newConfig()
+		config.MultiAZ = types.Bool{Value: true}
+		config.Replicas = types.Int64{Value: 4}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("replicas"),
+		))
+		config.Replicas = types.Int64{Value: 0}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("replicas"),
+		))
+	})
+
+	It("Checks the autoscaling limits", func() {
+		config := newConfig()
+		config.MultiAZ = types.Bool{Value: true}
+		config.AutoScalingEnabled = types.Bool{Value: true}
+		config.MinReplicas = types.Int64{Value: 2}
+		config.MaxReplicas = types.Int64{Value: 9}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("min_replicas"),
+		))
+		config.MinReplicas = types.Int64{Value: 9}
+		config.MaxReplicas = types.Int64{Value: 6}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("max_replicas"),
+		))
+	})
+
+	It("Rejects replicas when autoscaling is enabled", func() {
+		config := newConfig()
+		config.AutoScalingEnabled = types.Bool{Value: true}
+		config.Replicas = types.Int64{Value: 3}
+		config.MinReplicas = types.Int64{Value: 2}
+		config.MaxReplicas = types.Int64{Value: 4}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("replicas"),
+		))
+		config.Replicas = types.Int64{Unknown: true}
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+	})
+
+	It("Requires both autoscaling limits when autoscaling is enabled", func() {
+		config := newConfig()
+		config.AutoScalingEnabled = types.Bool{Value: true}
+		config.MinReplicas = types.Int64{Value: 2}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("max_replicas"),
+		))
+		config.MaxReplicas = types.Int64{Unknown: true}
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+	})
+
+	It("Rejects autoscaling limits when autoscaling isn't enabled", func() {
+		config := newConfig()
+		config.MinReplicas = types.Int64{Value: 2}
+		config.MaxReplicas = types.Int64{Value: 4}
+		Expect(errorPaths(validateClusterTopology(config))).To(ConsistOf(
+			attributePath("min_replicas"),
+			attributePath("max_replicas"),
+		))
+		config.AutoScalingEnabled = types.Bool{Value: false}
+		Expect(errorPaths(validateClusterTopology(config))).To(HaveLen(2))
+		config.MinReplicas = types.Int64{Unknown: true}
+		config.MaxReplicas = types.Int64{Unknown: true}
+		Expect(validateClusterTopology(config)).To(BeEmpty())
+	})
+
+	It("Uses the maximum replicas as compute nodes when autoscaling", func() {
+		config := newConfig()
+		config.Replicas = types.Int64{Value: 2}
+		config.MaxReplicas = types.Int64{Value: 5}
+		Expect(config.ComputeNodes()).To(Equal(types.Int64{Value: 2}))
+		config.AutoScalingEnabled = types.Bool{Value: true}
+		Expect(config.ComputeNodes()).To(Equal(types.Int64{Value: 5}))
+		config.AutoScalingEnabled = types.Bool{Unknown: true}
+		Expect(config.ComputeNodes().Unknown).To(BeTrue())
+	})
+})
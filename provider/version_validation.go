@@ -0,0 +1,272 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	semver "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+const (
+	versionPrefix        = "openshift-v"
+	defaultChannelGroup  = "stable"
+	closestVersionsCount = 5
+)
+
+// channelGroups contains the channel groups that can be used in the 'channel_group' attribute of
+// the cluster resources.
+var channelGroups = []string{"stable", "fast", "candidate", "nightly"}
+
+// isValidChannelGroup checks if the given channel group is one of the supported ones.
+func isValidChannelGroup(channelGroup string) bool {
+	for _, group := range channelGroups {
+		if group == channelGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// channelGroupOrDefault returns the value of the given channel group attribute, or the default
+// channel group if it hasn't been set.
+func channelGroupOrDefault(channelGroup types.String) string {
+	if channelGroup.Unknown || channelGroup.Null || channelGroup.Value == "" {
+		return defaultChannelGroup
+	}
+	return channelGroup.Value
+}
+
+// rawVersion removes from the given version identifier the 'openshift-v' prefix and the channel
+// group suffix, if present. For example 'openshift-v4.12.0-fc.1-candidate' is converted into
+// '4.12.0-fc.1'.
+func rawVersion(version string) string {
+	result := strings.TrimPrefix(version, versionPrefix)
+	for _, group := range channelGroups {
+		result = strings.TrimSuffix(result, "-"+group)
+	}
+	return result
+}
+
+// versionChannelGroup returns the channel group of the suffix of the given version identifier, or
+// an empty string if it doesn't have that suffix.
+func versionChannelGroup(version string) string {
+	for _, group := range channelGroups {
+		if strings.HasSuffix(version, "-"+group) {
+			return group
+		}
+	}
+	return ""
+}
+
+// checkVersionChannelGroup checks that the channel group suffix of the given version, if present,
+// is the given channel group.
+func checkVersionChannelGroup(version string, channelGroup string) error {
+	suffix := versionChannelGroup(version)
+	if suffix != "" && suffix != channelGroup {
+		return fmt.Errorf(
+			"version '%s' has the suffix of channel group '%s' but the channel group is '%s'",
+			version, suffix, channelGroup,
+		)
+	}
+	return nil
+}
+
+// parseVersion parses a version identifier, with or without the 'openshift-v' prefix and the
+// channel group suffix.
+func parseVersion(version string) (*semver.Version, error) {
+	return semver.NewVersion(rawVersion(version))
+}
+
+// versionID returns the identifier that the API uses for the given version and channel group.
+// Versions outside the stable channel group have the channel group as suffix, for example
+// 'openshift-v4.12.0-fc.1-candidate'. The given version may already contain the prefix or the
+// suffix, in that case they are replaced by the ones that correspond to the channel group. Versions
+// with the suffix of a different channel group are rejected by checkVersionChannelGroup.
+func versionID(version string, channelGroup string) string {
+	id := versionPrefix + rawVersion(version)
+	if channelGroup != defaultChannelGroup {
+		id = id + "-" + channelGroup
+	}
+	return id
+}
+
+// closestVersions returns the versions of the given list that are closest to the given one,
+// sorted in ascending order.
+func closestVersions(version *semver.Version, candidates []*semver.Version, count int) []string {
+	sorted := make([]*semver.Version, len(candidates))
+	copy(sorted, candidates)
+	sort.Sort(semver.Collection(sorted))
+	position := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].GreaterThanOrEqual(version)
+	})
+	start := position - count/2
+	if start+count > len(sorted) {
+		start = len(sorted) - count
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + count
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+	result := make([]string, 0, end-start)
+	for _, candidate := range sorted[start:end] {
+		result = append(result, candidate.Original())
+	}
+	return result
+}
+
+// validateVersion checks that the given version is enabled in the versions catalog of OCM, that it
+// belongs to the given channel group and, for ROSA clusters, that it is available for ROSA. If it
+// isn't valid the returned error lists the closest valid versions.
+func validateVersion(ctx context.Context, collection *cmv1.VersionsClient, version string,
+	channelGroup string, product string) error {
+	err := checkVersionChannelGroup(version, channelGroup)
+	if err != nil {
+		return err
+	}
+	requested, err := parseVersion(version)
+	if err != nil {
+		return fmt.Errorf("version '%s' isn't a valid version: %v", version, err)
+	}
+
+	// Fetch the valid versions:
+	search := fmt.Sprintf("enabled = 't' and channel_group = '%s'", channelGroup)
+	if product == rosaProduct {
+		search = search + " and rosa_enabled = 't'"
+	}
+	var candidates []*semver.Version
+	id := versionID(version, channelGroup)
+	found := false
+	listSize := 100
+	listPage := 1
+	listRequest := collection.List().Search(search).Size(listSize)
+	for {
+		listResponse, err := listRequest.SendContext(ctx)
+		if err != nil {
+			return fmt.Errorf("can't list versions: %v", err)
+		}
+		listResponse.Items().Each(func(item *cmv1.Version) bool {
+			if item.ID() == id || item.RawID() == rawVersion(version) {
+				found = true
+				return false
+			}
+			candidate, err := semver.NewVersion(item.RawID())
+			if err == nil {
+				candidates = append(candidates, candidate)
+			}
+			return true
+		})
+		if found || listResponse.Size() < listSize {
+			break
+		}
+		listPage++
+		listRequest.Page(listPage)
+	}
+	if found {
+		return nil
+	}
+
+	message := fmt.Sprintf(
+		"version '%s' isn't enabled in channel group '%s'",
+		version, channelGroup,
+	)
+	if product == rosaProduct {
+		message = fmt.Sprintf(
+			"version '%s' isn't enabled for ROSA in channel group '%s'",
+			version, channelGroup,
+		)
+	}
+	closest := closestVersions(requested, candidates, closestVersionsCount)
+	if len(closest) > 0 {
+		message = fmt.Sprintf(
+			"%s, the closest valid versions are: %s",
+			message, strings.Join(closest, ", "),
+		)
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// modifyPlanValidateVersion is used by the cluster resources to check at plan time the 'version'
+// and 'channel_group' attributes.
+func modifyPlanValidateVersion(ctx context.Context, collection *cmv1.VersionsClient, product string,
+	request tfsdk.ModifyResourcePlanRequest, response *tfsdk.ModifyResourcePlanResponse) {
+	// Nothing to check when the resource is being destroyed:
+	if request.Plan.Raw.IsNull() {
+		return
+	}
+
+	versionPath := tftypes.NewAttributePath().WithAttributeName("version")
+	channelGroupPath := tftypes.NewAttributePath().WithAttributeName("channel_group")
+	var version, channelGroup types.String
+	diags := request.Plan.GetAttribute(ctx, versionPath, &version)
+	response.Diagnostics.Append(diags...)
+	diags = request.Plan.GetAttribute(ctx, channelGroupPath, &channelGroup)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	if !channelGroup.Unknown && !channelGroup.Null && !isValidChannelGroup(channelGroup.Value) {
+		response.Diagnostics.AddAttributeError(
+			channelGroupPath,
+			"Invalid channel group",
+			fmt.Sprintf(
+				"Channel group '%s' isn't valid, it should be one of: %s",
+				channelGroup.Value, strings.Join(channelGroups, ", "),
+			),
+		)
+		return
+	}
+	if version.Unknown || version.Null {
+		return
+	}
+
+	// Only check the version when it is going to be sent to the server:
+	if !request.State.Raw.IsNull() {
+		var stateVersion, stateChannelGroup types.String
+		diags = request.State.GetAttribute(ctx, versionPath, &stateVersion)
+		response.Diagnostics.Append(diags...)
+		diags = request.State.GetAttribute(ctx, channelGroupPath, &stateChannelGroup)
+		response.Diagnostics.Append(diags...)
+		if response.Diagnostics.HasError() {
+			return
+		}
+		if stateVersion.Equal(version) &&
+			channelGroupOrDefault(stateChannelGroup) == channelGroupOrDefault(channelGroup) {
+			return
+		}
+	}
+
+	err := validateVersion(ctx, collection, version.Value, channelGroupOrDefault(channelGroup), product)
+	if err != nil {
+		response.Diagnostics.AddAttributeError(
+			versionPath,
+			"Invalid version",
+			fmt.Sprintf("Invalid value for attribute 'version': %v", err),
+		)
+	}
+}
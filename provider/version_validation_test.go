@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	semver "github.com/hashicorp/go-version"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Version validation", func() {
+	Context("parseVersion", func() {
+		It("Parses a version with prefix, pre-release and channel group suffix", func() {
+			version, err := parseVersion("openshift-v4.12.0-fc.1-candidate")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Original()).To(Equal("4.12.0-fc.1"))
+			Expect(version.Prerelease()).To(Equal("fc.1"))
+			Expect(version.Core().String()).To(Equal("4.12.0"))
+		})
+		It("Parses a raw version", func() {
+			version, err := parseVersion("4.11.1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.String()).To(Equal("4.11.1"))
+		})
+		It("Fails with an invalid version", func() {
+			_, err := parseVersion("openshift-va.4.1")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("versionID", func() {
+		It("Adds the prefix to stable versions", func() {
+			Expect(versionID("4.11.1", "stable")).To(Equal("openshift-v4.11.1"))
+		})
+		It("Adds the prefix and the suffix to other channel groups", func() {
+			Expect(versionID("4.12.0-fc.1", "candidate")).To(Equal("openshift-v4.12.0-fc.1-candidate"))
+		})
+		It("Keeps complete identifiers", func() {
+			Expect(versionID("openshift-v4.11.1-fast", "fast")).To(Equal("openshift-v4.11.1-fast"))
+		})
+		It("Adds the suffix to identifiers that only have the prefix", func() {
+			Expect(versionID("openshift-v4.12.5", "candidate")).To(Equal("openshift-v4.12.5-candidate"))
+		})
+	})
+
+	Context("checkVersionChannelGroup", func() {
+		It("Accepts versions without suffix", func() {
+			Expect(checkVersionChannelGroup("openshift-v4.12.5", "fast")).To(Succeed())
+			Expect(checkVersionChannelGroup("4.12.5", "stable")).To(Succeed())
+		})
+		It("Accepts the suffix of the channel group", func() {
+			Expect(checkVersionChannelGroup("openshift-v4.12.5-fast", "fast")).To(Succeed())
+		})
+		It("Rejects the suffix of other channel groups", func() {
+			err := checkVersionChannelGroup("openshift-v4.12.5-fast", "stable")
+			Expect(err).To(MatchError(
+				"version 'openshift-v4.12.5-fast' has the suffix of channel group 'fast' " +
+					"but the channel group is 'stable'",
+			))
+		})
+	})
+
+	Context("closestVersions", func() {
+		candidates := func(values ...string) []*semver.Version {
+			result := make([]*semver.Version, len(values))
+			for i, value := range values {
+				result[i] = semver.Must(semver.NewVersion(value))
+			}
+			return result
+		}
+
+		It("Returns the versions around the requested one", func() {
+			requested := semver.Must(semver.NewVersion("4.10.5"))
+			closest := closestVersions(requested, candidates(
+				"4.9.1", "4.10.1", "4.10.3", "4.10.7", "4.11.0", "4.11.1", "4.12.0",
+			), 4)
+			Expect(closest).To(Equal([]string{"4.10.1", "4.10.3", "4.10.7", "4.11.0"}))
+		})
+		It("Returns the latest versions when the requested one is newer", func() {
+			requested := semver.Must(semver.NewVersion("4.13.0"))
+			closest := closestVersions(requested, candidates("4.10.1", "4.11.0", "4.12.0"), 2)
+			Expect(closest).To(Equal([]string{"4.11.0", "4.12.0"}))
+		})
+		It("Returns nothing when there are no candidates", func() {
+			requested := semver.Must(semver.NewVersion("4.13.0"))
+			Expect(closestVersions(requested, nil, 5)).To(BeEmpty())
+		})
+	})
+})
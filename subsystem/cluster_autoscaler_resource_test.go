@@ -0,0 +1,487 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster autoscaler creation", func() {
+	// The autoscaler returned by the server, including the default values:
+	const autoscaler = `{
+	  "kind": "ClusterAutoscaler",
+	  "balance_similar_node_groups": true,
+	  "skip_nodes_with_local_storage": false,
+	  "max_node_provision_time": "15m",
+	  "resource_limits": {
+	    "max_nodes_total": 20
+	  },
+	  "scale_down": {
+	    "enabled": true,
+	    "unneeded_time": "10m",
+	    "utilization_threshold": "0.4",
+	    "delay_after_add": "10m",
+	    "delay_after_delete": "0s",
+	    "delay_after_failure": "3m"
+	  }
+	}`
+
+	BeforeEach(func() {
+		// The first thing that the provider will do when creating the autoscaler is check
+		// that the cluster is ready, so we always need to prepare the server to respond to
+		// that:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+		)
+	})
+
+	It("Creates, updates and deletes the autoscaler", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				VerifyJSON(`{
+				  "kind": "ClusterAutoscaler",
+				  "balance_similar_node_groups": true,
+				  "resource_limits": {
+				    "max_nodes_total": 20
+				  },
+				  "scale_down": {
+				    "unneeded_time": "10m",
+				    "utilization_threshold": "0.4"
+				  }
+				}`),
+				RespondWithJSON(http.StatusCreated, autoscaler),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster                     = "123"
+		    balance_similar_node_groups = true
+		    resource_limits = {
+		      max_nodes_total = 20
+		    }
+		    scale_down = {
+		      unneeded_time         = "10m"
+		      utilization_threshold = "0.4"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_autoscaler", "my_autoscaler")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.balance_similar_node_groups", true))
+		Expect(resource).To(MatchJQ(".attributes.skip_nodes_with_local_storage", nil))
+		Expect(resource).To(MatchJQ(".attributes.max_node_provision_time", nil))
+		Expect(resource).To(MatchJQ(".attributes.resource_limits.max_nodes_total", 20.0))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.enabled", nil))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.utilization_threshold", "0.4"))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.delay_after_failure", nil))
+
+		// Changing the scale down delay should send the configured values:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				VerifyJQ(`.balance_similar_node_groups`, true),
+				VerifyJQ(`.skip_nodes_with_local_storage`, true),
+				VerifyJQ(`.resource_limits.max_nodes_total`, 20.0),
+				VerifyJQ(`.scale_down.unneeded_time`, "10m"),
+				VerifyJQ(`.scale_down.utilization_threshold`, "0.4"),
+				VerifyJQ(`.scale_down.delay_after_add`, "20m"),
+				RespondWithPatchedJSON(http.StatusOK, autoscaler, `[
+				  {
+				    "op": "replace",
+				    "path": "/skip_nodes_with_local_storage",
+				    "value": true
+				  },
+				  {
+				    "op": "replace",
+				    "path": "/scale_down/delay_after_add",
+				    "value": "20m"
+				  }
+				]`),
+			),
+		)
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster                       = "123"
+		    balance_similar_node_groups   = true
+		    skip_nodes_with_local_storage = true
+		    resource_limits = {
+		      max_nodes_total = 20
+		    }
+		    scale_down = {
+		      unneeded_time         = "10m"
+		      utilization_threshold = "0.4"
+		      delay_after_add       = "20m"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource = terraform.Resource("ocm_cluster_autoscaler", "my_autoscaler")
+		Expect(resource).To(MatchJQ(".attributes.skip_nodes_with_local_storage", true))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.delay_after_add", "20m"))
+
+		// Destroying the resource should delete the autoscaler:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+		)
+		Expect(terraform.Destroy()).To(BeZero())
+	})
+
+	It("Doesn't save the values that aren't configured", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				VerifyJSON(`{
+				  "kind": "ClusterAutoscaler",
+				  "max_node_provision_time": "15m"
+				}`),
+				RespondWithJSON(http.StatusCreated, autoscaler),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster                 = "123"
+		    max_node_provision_time = "15m"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_autoscaler", "my_autoscaler")
+		Expect(resource).To(MatchJQ(".attributes.max_node_provision_time", "15m"))
+		Expect(resource).To(MatchJQ(".attributes.balance_similar_node_groups", nil))
+		Expect(resource).To(MatchJQ(".attributes.resource_limits", nil))
+		Expect(resource).To(MatchJQ(".attributes.scale_down", nil))
+
+		// Applying again shouldn't change anything, only read the autoscaler:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+		)
+		Expect(terraform.Apply()).To(BeZero())
+	})
+
+	It("Replaces the autoscaler when a value is removed", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				VerifyJSON(`{
+				  "kind": "ClusterAutoscaler",
+				  "max_node_provision_time": "15m",
+				  "scale_down": {
+				    "unneeded_time": "10m",
+				    "utilization_threshold": "0.4"
+				  }
+				}`),
+				RespondWithJSON(http.StatusCreated, autoscaler),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster                 = "123"
+		    max_node_provision_time = "15m"
+		    scale_down = {
+		      unneeded_time         = "10m"
+		      utilization_threshold = "0.4"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Removing values should delete the autoscaler and create it again without them,
+		// so that the server uses the defaults:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				VerifyJSON(`{
+				  "kind": "ClusterAutoscaler",
+				  "scale_down": {
+				    "utilization_threshold": "0.4"
+				  }
+				}`),
+				RespondWithPatchedJSON(http.StatusCreated, autoscaler, `[
+				  {
+				    "op": "replace",
+				    "path": "/max_node_provision_time",
+				    "value": "10m"
+				  }
+				]`),
+			),
+		)
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster = "123"
+		    scale_down = {
+		      utilization_threshold = "0.4"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_autoscaler", "my_autoscaler")
+		Expect(resource).To(MatchJQ(".attributes.max_node_provision_time", nil))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.unneeded_time", nil))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.utilization_threshold", "0.4"))
+	})
+})
+
+var _ = Describe("Cluster autoscaler validation", func() {
+	It("Fails if a duration isn't valid", func() {
+		// Run the apply command, no request should be sent:
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster = "123"
+		    scale_down = {
+		      delay_after_add = "10 minutes"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the utilization threshold isn't valid", func() {
+		// Run the apply command, no request should be sent:
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster = "123"
+		    scale_down = {
+		      utilization_threshold = "50%"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
+
+var _ = Describe("Cluster autoscaler import", func() {
+	It("Can import the autoscaler using the cluster identifier", func() {
+		// Prepare the server, the autoscaler is retrieved once to import it and once more
+		// to refresh it:
+		const autoscaler = `{
+		  "kind": "ClusterAutoscaler",
+		  "balance_similar_node_groups": false,
+		  "skip_nodes_with_local_storage": true,
+		  "scale_down": {
+		    "enabled": true,
+		    "utilization_threshold": "0.5"
+		  }
+		}`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Import("ocm_cluster_autoscaler.my_autoscaler", "123")).To(BeZero())
+
+		// Check the state, only the cluster is saved so that the values that aren't in the
+		// configuration keep using the server defaults:
+		resource := terraform.Resource("ocm_cluster_autoscaler", "my_autoscaler")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.balance_similar_node_groups", nil))
+		Expect(resource).To(MatchJQ(".attributes.skip_nodes_with_local_storage", nil))
+		Expect(resource).To(MatchJQ(".attributes.resource_limits", nil))
+		Expect(resource).To(MatchJQ(".attributes.scale_down", nil))
+	})
+
+	It("Updates the imported autoscaler without replacing it", func() {
+		// Prepare the server, the autoscaler is retrieved once to import it and once more
+		// to refresh it:
+		const autoscaler = `{
+		  "kind": "ClusterAutoscaler",
+		  "balance_similar_node_groups": false,
+		  "skip_nodes_with_local_storage": true,
+		  "max_node_provision_time": "15m",
+		  "scale_down": {
+		    "enabled": true,
+		    "utilization_threshold": "0.5"
+		  }
+		}`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster_autoscaler" "my_autoscaler" {
+		    cluster                 = "123"
+		    max_node_provision_time = "20m"
+		    scale_down = {
+		      utilization_threshold = "0.5"
+		    }
+		  }
+		`)
+		Expect(terraform.Import("ocm_cluster_autoscaler.my_autoscaler", "123")).To(BeZero())
+
+		// Applying the configuration should only update the configured values, the
+		// autoscaler shouldn't be deleted and created again:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				RespondWithJSON(http.StatusOK, autoscaler),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/autoscaler",
+				),
+				VerifyJSON(`{
+				  "kind": "ClusterAutoscaler",
+				  "max_node_provision_time": "20m",
+				  "scale_down": {
+				    "utilization_threshold": "0.5"
+				  }
+				}`),
+				RespondWithPatchedJSON(http.StatusOK, autoscaler, `[
+				  {
+				    "op": "replace",
+				    "path": "/max_node_provision_time",
+				    "value": "20m"
+				  }
+				]`),
+			),
+		)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_autoscaler", "my_autoscaler")
+		Expect(resource).To(MatchJQ(".attributes.max_node_provision_time", "20m"))
+		Expect(resource).To(MatchJQ(".attributes.skip_nodes_with_local_storage", nil))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.utilization_threshold", "0.5"))
+		Expect(resource).To(MatchJQ(".attributes.scale_down.enabled", nil))
+	})
+})
@@ -0,0 +1,240 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster data source", func() {
+	// This is the cluster that will be returned by the server when asked to retrieve a cluster.
+	const template = `{
+	  "id": "123",
+	  "external_id": "a4a1b7a0-f2a5-4c5e-8d3b-2d2e1f9b6c1d",
+	  "name": "my-cluster",
+	  "state": "ready",
+	  "product": {
+	    "id": "rosa"
+	  },
+	  "cloud_provider": {
+	    "id": "aws"
+	  },
+	  "region": {
+	    "id": "us-west-1"
+	  },
+	  "multi_az": true,
+	  "api": {
+	    "url": "https://my-api.example.com"
+	  },
+	  "console": {
+	    "url": "https://my-console.example.com"
+	  },
+	  "dns": {
+	    "base_domain": "example.com"
+	  },
+	  "nodes": {
+	    "compute": 3,
+	    "compute_machine_type": {
+	      "id": "r5.xlarge"
+	    },
+	    "availability_zones": [
+	      "us-west-1a",
+	      "us-west-1b",
+	      "us-west-1c"
+	    ]
+	  },
+	  "ccs": {
+	    "enabled": true
+	  },
+	  "aws": {
+	    "account_id": "123456789012"
+	  },
+	  "proxy": {
+	    "http_proxy": "http://proxy.example.com",
+	    "https_proxy": "https://proxy.example.com"
+	  },
+	  "network": {
+	    "machine_cidr": "10.0.0.0/16",
+	    "service_cidr": "172.30.0.0/16",
+	    "pod_cidr": "10.128.0.0/14",
+	    "host_prefix": 23
+	  },
+	  "version": {
+	    "id": "openshift-v4.11.1",
+	    "channel_group": "stable"
+	  }
+	}`
+
+	It("Finds a ROSA cluster by identifier", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_cluster_rosa_classic" "my_cluster" {
+		    id = "123"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.id", "123"))
+		Expect(resource).To(MatchJQ(".attributes.name", "my-cluster"))
+		Expect(resource).To(MatchJQ(".attributes.external_id", "a4a1b7a0-f2a5-4c5e-8d3b-2d2e1f9b6c1d"))
+		Expect(resource).To(MatchJQ(".attributes.state", "ready"))
+		Expect(resource).To(MatchJQ(".attributes.cloud_region", "us-west-1"))
+		Expect(resource).To(MatchJQ(".attributes.aws_account_id", "123456789012"))
+		Expect(resource).To(MatchJQ(".attributes.domain", "my-cluster.example.com"))
+		Expect(resource).To(MatchJQ(".attributes.availability_zones[1]", "us-west-1b"))
+		Expect(resource).To(MatchJQ(".attributes.proxy.http_proxy", "http://proxy.example.com"))
+		Expect(resource).To(MatchJQ(".attributes.version", "openshift-v4.11.1"))
+		Expect(resource).To(MatchJQ(".attributes.channel_group", "stable"))
+	})
+
+	It("Finds a ROSA cluster by name", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("search", "name = 'my-cluster'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 1,
+				  "total": 1,
+				  "items": [
+				    {
+				      "id": "123",
+				      "name": "my-cluster"
+				    }
+				  ]
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_cluster_rosa_classic" "my_cluster" {
+		    name = "my-cluster"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.id", "123"))
+		Expect(resource).To(MatchJQ(".attributes.api_url", "https://my-api.example.com"))
+	})
+
+	It("Finds a cluster by external identifier", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("search", "external_id = 'a4a1b7a0-f2a5-4c5e-8d3b-2d2e1f9b6c1d'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 1,
+				  "total": 1,
+				  "items": [
+				    {
+				      "id": "123",
+				      "name": "my-cluster"
+				    }
+				  ]
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_cluster" "my_cluster" {
+		    external_id = "a4a1b7a0-f2a5-4c5e-8d3b-2d2e1f9b6c1d"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.id", "123"))
+		Expect(resource).To(MatchJQ(".attributes.product", "rosa"))
+		Expect(resource).To(MatchJQ(".attributes.cloud_provider", "aws"))
+		Expect(resource).To(MatchJQ(".attributes.compute_nodes", 3.0))
+	})
+
+	It("Fails if the name is ambiguous", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("search", "name = 'my-cluster'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 2,
+				  "total": 2,
+				  "items": [
+				    {
+				      "id": "123",
+				      "name": "my-cluster"
+				    },
+				    {
+				      "id": "456",
+				      "name": "my-cluster"
+				    }
+				  ]
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_cluster_rosa_classic" "my_cluster" {
+		    name = "my-cluster"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if more than one selector is set", func() {
+		// Run the apply command, no request should be sent:
+		terraform.Source(`
+		  data "ocm_cluster_rosa_classic" "my_cluster" {
+		    id   = "123"
+		    name = "my-cluster"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
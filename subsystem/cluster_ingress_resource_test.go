@@ -0,0 +1,421 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster ingress creation", func() {
+	BeforeEach(func() {
+		// The first thing that the provider will do when creating an ingress is check that
+		// the cluster is ready, so we always need to prepare the server to respond to that:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+		)
+	})
+
+	It("Creates an additional ingress and deletes it", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses",
+				),
+				VerifyJSON(`{
+				  "kind": "Ingress",
+				  "listening": "internal",
+				  "route_selectors": {
+				    "route": "internal"
+				  },
+				  "excluded_namespaces": ["openshift-console"]
+				}`),
+				RespondWithJSON(http.StatusCreated, `{
+				  "id": "abcd",
+				  "default": false,
+				  "dns_name": "apps2.my-cluster.example.com",
+				  "listening": "internal",
+				  "route_selectors": {
+				    "route": "internal"
+				  },
+				  "excluded_namespaces": ["openshift-console"],
+				  "route_namespace_ownership_policy": "Strict",
+				  "route_wildcard_policy": "WildcardsDisallowed"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "my_ingress" {
+		    cluster             = "123"
+		    listening           = "internal"
+		    route_selectors     = {
+		      route = "internal"
+		    }
+		    excluded_namespaces = ["openshift-console"]
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_ingress", "my_ingress")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "abcd"))
+		Expect(resource).To(MatchJQ(".attributes.default", false))
+		Expect(resource).To(MatchJQ(".attributes.dns_name", "apps2.my-cluster.example.com"))
+		Expect(resource).To(MatchJQ(".attributes.listening", "internal"))
+		Expect(resource).To(MatchJQ(".attributes.route_selectors.route", "internal"))
+		Expect(resource).To(MatchJQ(".attributes.excluded_namespaces[0]", "openshift-console"))
+		Expect(resource).To(MatchJQ(".attributes.route_namespace_ownership_policy", "Strict"))
+		Expect(resource).To(MatchJQ(".attributes.route_wildcard_policy", "WildcardsDisallowed"))
+
+		// Destroying the resource should delete the ingress:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/abcd",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "abcd",
+				  "default": false,
+				  "listening": "internal",
+				  "route_selectors": {
+				    "route": "internal"
+				  },
+				  "excluded_namespaces": ["openshift-console"],
+				  "route_namespace_ownership_policy": "Strict",
+				  "route_wildcard_policy": "WildcardsDisallowed"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/abcd",
+				),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+		)
+		Expect(terraform.Destroy()).To(BeZero())
+	})
+
+	It("Adopts, updates and resets the default ingress", func() {
+		// The default ingress as created by the server:
+		const ingress = `{
+		  "id": "a1b2",
+		  "default": true,
+		  "dns_name": "apps.my-cluster.example.com",
+		  "listening": "external",
+		  "route_namespace_ownership_policy": "Strict",
+		  "route_wildcard_policy": "WildcardsDisallowed"
+		}`
+
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "kind": "IngressList",
+				  "page": 1,
+				  "size": 2,
+				  "total": 2,
+				  "items": [
+				    {
+				      "id": "abcd",
+				      "default": false,
+				      "listening": "internal"
+				    },
+				    `+ingress+`
+				  ]
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/a1b2",
+				),
+				VerifyJSON(`{
+				  "kind": "Ingress",
+				  "listening": "internal",
+				  "route_namespace_ownership_policy": "InterNamespaceAllowed",
+				  "route_selectors": {
+				    "route": "internal"
+				  }
+				}`),
+				RespondWithPatchedJSON(http.StatusOK, ingress, `[
+				  {
+				    "op": "replace",
+				    "path": "/listening",
+				    "value": "internal"
+				  },
+				  {
+				    "op": "replace",
+				    "path": "/route_namespace_ownership_policy",
+				    "value": "InterNamespaceAllowed"
+				  },
+				  {
+				    "op": "add",
+				    "path": "/route_selectors",
+				    "value": {
+				      "route": "internal"
+				    }
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster                          = "123"
+		    default                          = true
+		    listening                        = "internal"
+		    route_namespace_ownership_policy = "InterNamespaceAllowed"
+		    route_selectors                  = {
+		      route = "internal"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_ingress", "default")
+		Expect(resource).To(MatchJQ(".attributes.id", "a1b2"))
+		Expect(resource).To(MatchJQ(".attributes.default", true))
+		Expect(resource).To(MatchJQ(".attributes.listening", "internal"))
+		Expect(resource).To(MatchJQ(".attributes.route_selectors.route", "internal"))
+		Expect(resource).To(MatchJQ(".attributes.route_wildcard_policy", "WildcardsDisallowed"))
+
+		// Removing the route selectors and allowing wildcards should send only those
+		// changes:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/a1b2",
+				),
+				RespondWithPatchedJSON(http.StatusOK, ingress, `[
+				  {
+				    "op": "replace",
+				    "path": "/listening",
+				    "value": "internal"
+				  },
+				  {
+				    "op": "replace",
+				    "path": "/route_namespace_ownership_policy",
+				    "value": "InterNamespaceAllowed"
+				  },
+				  {
+				    "op": "add",
+				    "path": "/route_selectors",
+				    "value": {
+				      "route": "internal"
+				    }
+				  }
+				]`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/a1b2",
+				),
+				VerifyJSON(`{
+				  "kind": "Ingress",
+				  "route_selectors": {},
+				  "route_wildcard_policy": "WildcardsAllowed"
+				}`),
+				RespondWithPatchedJSON(http.StatusOK, ingress, `[
+				  {
+				    "op": "replace",
+				    "path": "/listening",
+				    "value": "internal"
+				  },
+				  {
+				    "op": "replace",
+				    "path": "/route_namespace_ownership_policy",
+				    "value": "InterNamespaceAllowed"
+				  },
+				  {
+				    "op": "replace",
+				    "path": "/route_wildcard_policy",
+				    "value": "WildcardsAllowed"
+				  }
+				]`),
+			),
+		)
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster                          = "123"
+		    default                          = true
+		    listening                        = "internal"
+		    route_namespace_ownership_policy = "InterNamespaceAllowed"
+		    route_wildcard_policy            = "WildcardsAllowed"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource = terraform.Resource("ocm_cluster_ingress", "default")
+		Expect(resource).To(MatchJQ(".attributes.route_selectors", nil))
+		Expect(resource).To(MatchJQ(".attributes.route_wildcard_policy", "WildcardsAllowed"))
+
+		// Destroying the resource should reset the ingress instead of deleting it:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/a1b2",
+				),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/a1b2",
+				),
+				VerifyJSON(`{
+				  "kind": "Ingress",
+				  "excluded_namespaces": [],
+				  "listening": "external",
+				  "route_namespace_ownership_policy": "Strict",
+				  "route_selectors": {},
+				  "route_wildcard_policy": "WildcardsDisallowed"
+				}`),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+		)
+		Expect(terraform.Destroy()).To(BeZero())
+	})
+
+	It("Fails to adopt the default ingress if it doesn't exist", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "kind": "IngressList",
+				  "page": 1,
+				  "size": 0,
+				  "total": 0,
+				  "items": []
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster   = "123"
+		    default   = true
+		    listening = "internal"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
+
+var _ = Describe("Cluster ingress validation", func() {
+	It("Fails if the listening method isn't valid", func() {
+		// Run the apply command, no request should be sent:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster   = "123"
+		    default   = true
+		    listening = "private"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
+
+var _ = Describe("Cluster ingress import", func() {
+	It("Can import an ingress using the cluster and ingress identifiers", func() {
+		// Prepare the server, the ingress is retrieved once to import it and once more to
+		// refresh it:
+		const ingress = `{
+		  "id": "a1b2",
+		  "default": true,
+		  "dns_name": "apps.my-cluster.example.com",
+		  "listening": "internal",
+		  "route_selectors": {
+		    "route": "internal"
+		  },
+		  "route_namespace_ownership_policy": "Strict",
+		  "route_wildcard_policy": "WildcardsDisallowed"
+		}`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/a1b2",
+				),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/ingresses/a1b2",
+				),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Import("ocm_cluster_ingress.default", "123,a1b2")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_ingress", "default")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "a1b2"))
+		Expect(resource).To(MatchJQ(".attributes.default", true))
+		Expect(resource).To(MatchJQ(".attributes.listening", "internal"))
+		Expect(resource).To(MatchJQ(".attributes.route_selectors.route", "internal"))
+	})
+
+	It("Fails to import an ingress without the cluster identifier", func() {
+		// Run the import command, no request should be sent:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Import("ocm_cluster_ingress.default", "a1b2")).ToNot(BeZero())
+	})
+})
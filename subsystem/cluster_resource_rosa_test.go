@@ -17,7 +17,13 @@ limitations under the License.
 package provider
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
 	. "github.com/onsi/gomega"                         // nolint
@@ -25,6 +31,24 @@ import (
 	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
 )
 
+// proxyCA is a self signed certificate used as the additional trust bundle of the proxy.
+const proxyCA = `-----BEGIN CERTIFICATE-----
+MIIBfTCCASOgAwIBAgIUG1dOE32A7u8cwVxNbj7MELp0P3kwCgYIKoZIzj0EAwIw
+EzERMA8GA1UEAwwIcHJveHktY2EwIBcNMjYxMDE4MTQ0NzA0WhgPMjEyNjA5MjQx
+NDQ3MDRaMBMxETAPBgNVBAMMCHByb3h5LWNhMFkwEwYHKoZIzj0CAQYIKoZIzj0D
+AQcDQgAEjtJE6a9qhi7HRlDBLZ7Sl88fDnmLTexBzRD0H/QvvSmgrjJtZwvhYicD
+96CARxLRjWv4mTEe2SaQl8F4G3rppKNTMFEwHQYDVR0OBBYEFBQHZs+XPDqjWrGi
+ghxqM3Txfn9XMB8GA1UdIwQYMBaAFBQHZs+XPDqjWrGighxqM3Txfn9XMA8GA1Ud
+EwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDSAAwRQIgETcgyAF4gMNoSCw78vWYZ1PD
+acWJzAna9gZjGC9zrIkCIQCpyxjXq0Bw84M2YXzo6ijeS4vY9nzdk8owpTG4hKEe
+mg==
+-----END CERTIFICATE-----
+`
+
+// proxyCAHCL is the proxy certificate with the line breaks escaped, so that it can be used inside
+// a string of a Terraform configuration.
+var proxyCAHCL = strings.ReplaceAll(proxyCA, "\n", `\n`)
+
 var _ = Describe("Cluster creation", func() {
 	// This is the cluster that will be returned by the server when asked to create or retrieve
 	// a cluster.
@@ -111,6 +135,210 @@ var _ = Describe("Cluster creation", func() {
 		Expect(terraform.Apply()).To(BeZero())
 	})
 
+	It("Creates cluster with a version enabled for ROSA", func() {
+		// Prepare the server:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/versions",
+			CombineHandlers(
+				VerifyFormKV("search", "enabled = 't' and channel_group = 'fast' and rosa_enabled = 't'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 1,
+				  "total": 1,
+				  "items": [
+				    {
+				      "id": "openshift-v4.11.1-fast",
+				      "raw_id": "4.11.1",
+				      "enabled": true,
+				      "rosa_enabled": true,
+				      "channel_group": "fast"
+				    }
+				  ]
+				}`),
+			),
+		)
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.version.id`, "openshift-v4.11.1-fast"),
+				VerifyJQ(`.version.channel_group`, "fast"),
+				RespondWithPatchedJSON(http.StatusCreated, template, `[
+					{
+					  "op": "replace",
+					  "path": "/version",
+					  "value": {
+						"id": "openshift-v4.11.1-fast",
+						"channel_group": "fast"
+					  }
+					}]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			version        = "openshift-v4.11.1-fast"
+			channel_group  = "fast"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.version", "openshift-v4.11.1-fast"))
+		Expect(resource).To(MatchJQ(".attributes.channel_group", "fast"))
+	})
+
+	It("Fails if the version isn't enabled for ROSA", func() {
+		// Prepare the server, the cluster shouldn't be created:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/versions",
+			CombineHandlers(
+				VerifyFormKV("search", "enabled = 't' and channel_group = 'stable' and rosa_enabled = 't'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 1,
+				  "total": 1,
+				  "items": [
+				    {
+				      "id": "openshift-v4.11.1",
+				      "raw_id": "4.11.1",
+				      "enabled": true,
+				      "rosa_enabled": true,
+				      "channel_group": "stable"
+				    }
+				  ]
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			version        = "openshift-v4.12.0-fc.1"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	Context("Test import cluster", func() {
+		BeforeEach(func() {
+			terraform.Source(`
+			  resource "ocm_cluster_rosa_classic" "my_cluster" {
+			    name           = "my-cluster"
+			    cloud_region   = "us-west-1"
+			    aws_account_id = "123"
+			  }
+			`)
+		})
+
+		It("Imports a cluster by identifier", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+			)
+			Expect(terraform.Import("ocm_cluster_rosa_classic.my_cluster", "123")).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.id", "123"))
+			Expect(resource).To(MatchJQ(".attributes.name", "my-cluster"))
+		})
+
+		It("Imports a cluster by name", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+					VerifyFormKV("search", "name = 'my-cluster'"),
+					RespondWithJSON(http.StatusOK, `{
+					  "page": 1,
+					  "size": 1,
+					  "total": 1,
+					  "items": [
+					    {
+					      "id": "123",
+					      "name": "my-cluster"
+					    }
+					  ]
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+			)
+			Expect(terraform.Import("ocm_cluster_rosa_classic.my_cluster", "name:my-cluster")).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.id", "123"))
+		})
+
+		It("Imports a cluster by external identifier", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+					VerifyFormKV("search", "external_id = 'a4a1b7a0-f2a5-4c5e-8d3b-2d2e1f9b6c1d'"),
+					RespondWithJSON(http.StatusOK, `{
+					  "page": 1,
+					  "size": 1,
+					  "total": 1,
+					  "items": [
+					    {
+					      "id": "123",
+					      "name": "my-cluster"
+					    }
+					  ]
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+			)
+			Expect(terraform.Import(
+				"ocm_cluster_rosa_classic.my_cluster",
+				"a4a1b7a0-f2a5-4c5e-8d3b-2d2e1f9b6c1d",
+			)).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.id", "123"))
+		})
+
+		It("Fails to import if no cluster has the name", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+					VerifyFormKV("search", "name = 'my-cluster'"),
+					RespondWithJSON(http.StatusOK, `{
+					  "page": 1,
+					  "size": 0,
+					  "total": 0,
+					  "items": []
+					}`),
+				),
+			)
+			Expect(terraform.Import("ocm_cluster_rosa_classic.my_cluster", "name:my-cluster")).ToNot(BeZero())
+		})
+	})
+
 	Context("Test destroy cluster", func() {
 
 		BeforeEach(func() {
@@ -137,6 +365,10 @@ var _ = Describe("Cluster creation", func() {
 					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
 					RespondWithJSON(http.StatusOK, templateReadyState),
 				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
 				CombineHandlers(
 					VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123"),
 					RespondWithJSON(http.StatusOK, templateReadyState),
@@ -223,8 +455,157 @@ var _ = Describe("Cluster creation", func() {
 		})
 	})
 
+	Context("Test deletion protection", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyJQ(`.name`, "my-cluster"),
+					VerifyJQ(`.delete_protection.enabled`, true),
+					RespondWithJSON(http.StatusCreated, templateReadyState),
+				),
+			)
+			terraform.Source(`
+				  resource "ocm_cluster_rosa_classic" "my_cluster" {
+					name           = "my-cluster"
+					cloud_region   = "us-west-1"
+					aws_account_id = "123"
+					deletion_protection = true
+					disable_waiting_in_destroy = true
+				  }
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+		})
+
+		It("Fails to destroy a protected cluster without sending the delete request", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+			)
+			Expect(terraform.Destroy()).ToNot(BeZero())
+
+			// Check the state:
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.deletion_protection", true))
+		})
+
+		It("Keeps the protection if the cluster update fails", func() {
+			// The delete protection shouldn't be updated:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusBadRequest, `{
+					  "kind": "Error",
+					  "reason": "Cluster can't be updated"
+					}`),
+				),
+			)
+			terraform.Source(`
+				  resource "ocm_cluster_rosa_classic" "my_cluster" {
+					name           = "my-cluster"
+					cloud_region   = "us-west-1"
+					aws_account_id = "123"
+					deletion_protection = false
+					disable_waiting_in_destroy = true
+				  }
+			`)
+			Expect(terraform.Apply()).ToNot(BeZero())
+
+			// Check the state:
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.deletion_protection", true))
+		})
+
+		It("Reads the protection disabled outside of Terraform", func() {
+			// Applying again should detect that the protection has been disabled and enable
+			// it again:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, templateReadyState, `[
+					  {
+					    "op": "add",
+					    "path": "/delete_protection",
+					    "value": {
+					      "enabled": false
+					    }
+					  }
+					]`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/delete_protection"),
+					VerifyJQ(`.enabled`, true),
+					RespondWithJSON(http.StatusOK, `{}`),
+				),
+			)
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Check the state:
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.deletion_protection", true))
+		})
+
+		It("Destroys the cluster after disabling the protection", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/delete_protection"),
+					VerifyJQ(`.enabled`, false),
+					RespondWithJSON(http.StatusOK, `{}`),
+				),
+			)
+			terraform.Source(`
+				  resource "ocm_cluster_rosa_classic" "my_cluster" {
+					name           = "my-cluster"
+					cloud_region   = "us-west-1"
+					aws_account_id = "123"
+					deletion_protection = false
+					disable_waiting_in_destroy = true
+				  }
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Check the state:
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.deletion_protection", false))
+
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+			)
+			Expect(terraform.Destroy()).To(BeZero())
+		})
+	})
+
 	It("Creates cluster with http proxy", func() {
-		// Prepare the server:
+		// Prepare the server, it returns a placeholder instead of the trust bundle:
 		server.AppendHandlers(
 			CombineHandlers(
 				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
@@ -234,21 +615,28 @@ var _ = Describe("Cluster creation", func() {
 				VerifyJQ(`.product.id`, "rosa"),
 				VerifyJQ(`.proxy.http_proxy`, "http://proxy.com"),
 				VerifyJQ(`.proxy.https_proxy`, "http://proxy.com"),
-				VerifyJQ(`.additional_trust_bundle`, "123"),
+				VerifyJQ(`.proxy.no_proxy`, "example.com"),
+				VerifyJQ(`.additional_trust_bundle`, proxyCA),
 				RespondWithPatchedJSON(http.StatusOK, template, `[
 					{
 					  "op": "add",
 					  "path": "/proxy",
-					  "value": {						  
+					  "value": {
 						  "http_proxy" : "http://proxy.com",
-						  "https_proxy" : "http://proxy.com"
+						  "https_proxy" : "http://proxy.com",
+						  "no_proxy" : "example.com"
 					  }
 					},
 					{
 					  "op": "add",
-					  "path": "/",
+					  "path": "/additional_trust_bundle",
+					  "value": "REDACTED"
+					},
+					{
+					  "op": "add",
+					  "path": "/aws",
 					  "value": {
-						  "additional_trust_bundle" : "123"
+						  "subnet_ids": ["subnet-1", "subnet-2"]
 					  }
 					},
 					{
@@ -267,65 +655,254 @@ var _ = Describe("Cluster creation", func() {
 		// Run the apply command:
 		terraform.Source(`
 		  resource "ocm_cluster_rosa_classic" "my_cluster" {
-		    name           = "my-cluster"	
+		    name           = "my-cluster"
 		    cloud_region   = "us-west-1"
 			aws_account_id = "123"
+			aws_subnet_ids = ["subnet-1", "subnet-2"]
 			proxy = {
 				http_proxy = "http://proxy.com",
 				https_proxy = "http://proxy.com",
-				additional_trust_bundle = "123",
-			}			
+				no_proxy = "example.com",
+				additional_trust_bundle = "` + proxyCAHCL + `"
+			}
 		  }
 		`)
 		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.proxy.no_proxy", "example.com"))
+		Expect(resource).To(MatchJQ(".attributes.proxy.additional_trust_bundle", proxyCA))
 	})
 
-	It("Creates cluster with aws subnet ids & private link", func() {
-		// Prepare the server:
-		server.AppendHandlers(
-			CombineHandlers(
-				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
-				VerifyJQ(`.name`, "my-cluster"),
-				VerifyJQ(`.cloud_provider.id`, "aws"),
-				VerifyJQ(`.region.id`, "us-west-1"),
-				VerifyJQ(`.product.id`, "rosa"),
-				VerifyJQ(`.aws.subnet_ids.[0]`, "id1"),
-				VerifyJQ(`.aws.private_link`, true),
-				VerifyJQ(`.nodes.availability_zones.[0]`, "az1"),
-				VerifyJQ(`.api.listening`, "internal"),
-				RespondWithPatchedJSON(http.StatusOK, template, `[
-					{
-						"op": "add",
-						"path": "/availability_zones",
-						"value": ["az1", "az2", "az3"]
-					},					
-					{
-					  "op": "add",
-					  "path": "/aws",
-					  "value": {
-						  "private_link": true,
-						  "subnet_ids": ["id1", "id2", "id3"]
-					  }
-					},
-					{
-					  "op": "add",
-					  "path": "/nodes",
-					  "value": {
-						"compute": 3,
-						"compute_machine_type": {
-							"id": "r5.xlarge"
-						}
-					  }
-					}]`),
-			),
-		)
+	Context("Test update of the proxy", func() {
+		// clusterPatch returns the patch that adds to the cluster template the subnets and the
+		// given proxy and trust bundle:
+		clusterPatch := func(proxy, trustBundle string) string {
+			return `[
+				{
+				  "op": "add",
+				  "path": "/proxy",
+				  "value": ` + proxy + `
+				},
+				{
+				  "op": "add",
+				  "path": "/additional_trust_bundle",
+				  "value": "` + trustBundle + `"
+				},
+				{
+				  "op": "add",
+				  "path": "/aws",
+				  "value": {
+					  "subnet_ids": ["subnet-1", "subnet-2"]
+				  }
+				},
+				{
+				  "op": "add",
+				  "path": "/nodes",
+				  "value": {
+					"compute": 3,
+					"compute_machine_type": {
+						"id": "r5.xlarge"
+					}
+				  }
+				}
+			  ]`
+		}
+		source := func(proxy string) string {
+			return `
+			resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123"
+				aws_subnet_ids = ["subnet-1", "subnet-2"]
+				` + proxy + `
+			  }
+			`
+		}
+		firstProxy := `{"http_proxy": "http://first.com", "https_proxy": "http://first.com"}`
+		secondProxy := `{
+			"http_proxy": "http://second.com:3128",
+			"https_proxy": "https://second.com:3129",
+			"no_proxy": "example.com"
+		}`
 
-		// Run the apply command:
-		terraform.Source(`
-		  resource "ocm_cluster_rosa_classic" "my_cluster" {
-		    name           = "my-cluster"
-		    cloud_region   = "us-west-1"
+		It("Changes and removes the proxy", func() {
+			// Create the cluster:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyJQ(`.proxy.http_proxy`, "http://first.com"),
+					VerifyJQ(`.additional_trust_bundle`, nil),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(firstProxy, "")),
+				),
+			)
+			terraform.Source(source(`
+				proxy = {
+					http_proxy = "http://first.com"
+					https_proxy = "http://first.com"
+				}
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Change the proxy and add a trust bundle:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(firstProxy, "")),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.proxy.http_proxy`, "http://second.com:3128"),
+					VerifyJQ(`.proxy.https_proxy`, "https://second.com:3129"),
+					VerifyJQ(`.proxy.no_proxy`, "example.com"),
+					VerifyJQ(`.additional_trust_bundle`, proxyCA),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(secondProxy, "REDACTED")),
+				),
+			)
+			terraform.Source(source(`
+				proxy = {
+					http_proxy = "http://second.com:3128"
+					https_proxy = "https://second.com:3129"
+					no_proxy = "example.com"
+					additional_trust_bundle = "` + proxyCAHCL + `"
+				}
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.proxy.https_proxy", "https://second.com:3129"))
+			Expect(resource).To(MatchJQ(".attributes.proxy.additional_trust_bundle", proxyCA))
+
+			// Change only the trust bundle, the proxy shouldn't be sent:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(secondProxy, "REDACTED")),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.proxy`, nil),
+					VerifyJQ(`.additional_trust_bundle`, proxyCA+proxyCA),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(secondProxy, "REDACTED")),
+				),
+			)
+			terraform.Source(source(`
+				proxy = {
+					http_proxy = "http://second.com:3128"
+					https_proxy = "https://second.com:3129"
+					no_proxy = "example.com"
+					additional_trust_bundle = "` + proxyCAHCL + proxyCAHCL + `"
+				}
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Remove the proxy, empty values should be sent:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(secondProxy, "REDACTED")),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.proxy.http_proxy`, ""),
+					VerifyJQ(`.proxy.https_proxy`, ""),
+					VerifyJQ(`.proxy.no_proxy`, ""),
+					VerifyJQ(`.additional_trust_bundle`, ""),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(`{}`, "")),
+				),
+			)
+			terraform.Source(source(""))
+			Expect(terraform.Apply()).To(BeZero())
+			resource = terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(".attributes.proxy", nil))
+		})
+
+		It("Fails if the proxy is used without subnets", func() {
+			// Run the apply command, the cluster should not be requested:
+			terraform.Source(`
+			resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123"
+				proxy = {
+					http_proxy = "http://proxy.com"
+					https_proxy = "http://proxy.com"
+				}
+			  }
+			`)
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+
+		It("Fails if a proxy URL isn't valid", func() {
+			// Run the apply command, the cluster should not be requested:
+			terraform.Source(source(`
+				proxy = {
+					http_proxy = "proxy.com"
+					https_proxy = "http://proxy.com"
+				}
+			`))
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+
+		It("Fails if the trust bundle isn't valid", func() {
+			// Run the apply command, the cluster should not be requested:
+			terraform.Source(source(`
+				proxy = {
+					http_proxy = "http://proxy.com"
+					https_proxy = "http://proxy.com"
+					additional_trust_bundle = "123"
+				}
+			`))
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+	})
+
+	It("Creates cluster with aws subnet ids & private link", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.name`, "my-cluster"),
+				VerifyJQ(`.cloud_provider.id`, "aws"),
+				VerifyJQ(`.region.id`, "us-west-1"),
+				VerifyJQ(`.product.id`, "rosa"),
+				VerifyJQ(`.aws.subnet_ids.[0]`, "id1"),
+				VerifyJQ(`.aws.private_link`, true),
+				VerifyJQ(`.multi_az`, true),
+				VerifyJQ(`.nodes.availability_zones.[0]`, "az1"),
+				VerifyJQ(`.api.listening`, "internal"),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+					{
+						"op": "add",
+						"path": "/availability_zones",
+						"value": ["az1", "az2", "az3"]
+					},					
+					{
+					  "op": "add",
+					  "path": "/aws",
+					  "value": {
+						  "private_link": true,
+						  "subnet_ids": ["id1", "id2", "id3"]
+					  }
+					},
+					{
+					  "op": "add",
+					  "path": "/nodes",
+					  "value": {
+						"compute": 3,
+						"compute_machine_type": {
+							"id": "r5.xlarge"
+						}
+					  }
+					}]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
 			aws_account_id = "123"
+			multi_az = true
 			availability_zones = ["az1","az2","az3"]
 			aws_private_link = true
 			aws_subnet_ids = [
@@ -336,6 +913,102 @@ var _ = Describe("Cluster creation", func() {
 		Expect(terraform.Apply()).To(BeZero())
 	})
 
+	It("Fails if the number of availability zones doesn't match 'multi_az'", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			multi_az       = true
+			availability_zones = ["az1", "az2"]
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if PrivateLink doesn't have a subnet per availability zone", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			multi_az       = true
+			aws_private_link = true
+			aws_subnet_ids = [
+				"id1", "id2"
+			]
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the replicas aren't a multiple of three in a multi AZ cluster", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			multi_az       = true
+			replicas       = 4
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the minimum replicas are too low for a single AZ cluster", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name                = "my-cluster"
+		    cloud_region        = "us-west-1"
+			aws_account_id      = "123"
+			autoscaling_enabled = true
+			min_replicas        = 1
+			max_replicas        = 4
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if a subnet is outside the machine CIDR", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			machine_cidr   = "10.0.0.0/16"
+			aws_subnet_ids = [
+				"id1", "id2"
+			]
+			aws_subnet_cidr_blocks = [
+				"10.0.0.0/24", "10.1.0.0/24"
+			]
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the pod CIDR is too small for the maximum replicas", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name                = "my-cluster"
+		    cloud_region        = "us-west-1"
+			aws_account_id      = "123"
+			pod_cidr            = "10.128.0.0/20"
+			host_prefix         = 23
+			autoscaling_enabled = true
+			min_replicas        = 2
+			max_replicas        = 10
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
 	It("Creates cluster when private link is false", func() {
 		// Prepare the server:
 		server.AppendHandlers(
@@ -389,10 +1062,10 @@ var _ = Describe("Cluster creation", func() {
 				VerifyJQ(`.cloud_provider.id`, "aws"),
 				VerifyJQ(`.region.id`, "us-west-1"),
 				VerifyJQ(`.product.id`, "rosa"),
-				VerifyJQ(`.aws.sts.role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role"),
-				VerifyJQ(`.aws.sts.support_role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role"),
-				VerifyJQ(`.aws.sts.instance_iam_roles.master_role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role"),
-				VerifyJQ(`.aws.sts.instance_iam_roles.worker_role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"),
+				VerifyJQ(`.aws.sts.role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"),
+				VerifyJQ(`.aws.sts.support_role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.master_role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.worker_role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"),
 				VerifyJQ(`.aws.sts.operator_role_prefix`, "terraform-operator"),
 				VerifyJQ(`.nodes.autoscale_compute.kind`, "MachinePoolAutoscaling"),
 				VerifyJQ(`.nodes.autoscale_compute.max_replicas`, float64(4)),
@@ -407,11 +1080,11 @@ var _ = Describe("Cluster creation", func() {
 						  "sts" : {
 							  "oidc_endpoint_url": "https://oidc_endpoint_url",
 							  "thumbprint": "111111",
-							  "role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-							  "support_role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 							  "instance_iam_roles" : {
-								"master_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-								"worker_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 							  },
 							  "operator_role_prefix" : "terraform-operator"
 						  }
@@ -443,7 +1116,7 @@ var _ = Describe("Cluster creation", func() {
 		resource "ocm_cluster_rosa_classic" "my_cluster" {
 			name           = "my-cluster"	
 			cloud_region   = "us-west-1"
-			aws_account_id = "123"
+			aws_account_id = "123456789012"
 			autoscaling_enabled = "true"
 			min_replicas = "2"
 			max_replicas = "4"
@@ -452,11 +1125,11 @@ var _ = Describe("Cluster creation", func() {
 				"label_key2" = "label_value2"
 			}
 			sts = {
-				role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-				support_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+				role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 				instance_iam_roles = {
-				  master_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-				  worker_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 				},
 				"operator_role_prefix" : "terraform-operator"
 			}
@@ -477,11 +1150,11 @@ var _ = Describe("Cluster creation", func() {
 						  "sts" : {
 							  "oidc_endpoint_url": "https://oidc_endpoint_url",
 							  "thumbprint": "111111",
-							  "role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-							  "support_role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 							  "instance_iam_roles" : {
-								"master_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-								"worker_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 							  },
 							  "operator_role_prefix" : "terraform-operator"
 						  }
@@ -519,11 +1192,11 @@ var _ = Describe("Cluster creation", func() {
 						  "sts" : {
 							  "oidc_endpoint_url": "https://oidc_endpoint_url",
 							  "thumbprint": "111111",
-							  "role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-							  "support_role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 							  "instance_iam_roles" : {
-								"master_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-								"worker_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 							  },
 							  "operator_role_prefix" : "terraform-operator"
 						  }
@@ -554,7 +1227,7 @@ var _ = Describe("Cluster creation", func() {
 		resource "ocm_cluster_rosa_classic" "my_cluster" {
 			name           = "my-cluster"	
 			cloud_region   = "us-west-1"
-			aws_account_id = "123"
+			aws_account_id = "123456789012"
 			autoscaling_enabled = "true"
 			min_replicas = "3"
 			max_replicas = "4"
@@ -563,11 +1236,11 @@ var _ = Describe("Cluster creation", func() {
 				"label_key2" = "label_value2"
 			}
 			sts = {
-				role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-				support_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+				role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 				instance_iam_roles = {
-				  master_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-				  worker_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 				},
 				"operator_role_prefix" : "terraform-operator"
 			}
@@ -588,11 +1261,11 @@ var _ = Describe("Cluster creation", func() {
 						  "sts" : {
 							  "oidc_endpoint_url": "https://oidc_endpoint_url",
 							  "thumbprint": "111111",
-							  "role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-							  "support_role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 							  "instance_iam_roles" : {
-								"master_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-								"worker_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 							  },
 							  "operator_role_prefix" : "terraform-operator"
 						  }
@@ -628,11 +1301,11 @@ var _ = Describe("Cluster creation", func() {
 						  "sts" : {
 							  "oidc_endpoint_url": "https://oidc_endpoint_url",
 							  "thumbprint": "111111",
-							  "role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-							  "support_role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 							  "instance_iam_roles" : {
-								"master_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-								"worker_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 							  },
 							  "operator_role_prefix" : "terraform-operator"
 						  }
@@ -660,18 +1333,18 @@ var _ = Describe("Cluster creation", func() {
 		resource "ocm_cluster_rosa_classic" "my_cluster" {
 			name           = "my-cluster"	
 			cloud_region   = "us-west-1"
-			aws_account_id = "123" 
+			aws_account_id = "123456789012" 
 			replicas = 4
 			compute_labels = {
 				"label_key1" = "label_value1", 
 				"label_key2" = "label_value2"
 			}
 			sts = {
-				role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-				support_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+				role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 				instance_iam_roles = {
-				  master_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-				  worker_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 				},
 				"operator_role_prefix" : "terraform-operator"
 			}
@@ -689,14 +1362,16 @@ var _ = Describe("Cluster creation", func() {
 				VerifyJQ(`.cloud_provider.id`, "aws"),
 				VerifyJQ(`.region.id`, "us-west-1"),
 				VerifyJQ(`.product.id`, "rosa"),
-				VerifyJQ(`.aws.sts.role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role"),
-				VerifyJQ(`.aws.sts.support_role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role"),
-				VerifyJQ(`.aws.sts.instance_iam_roles.master_role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role"),
-				VerifyJQ(`.aws.sts.instance_iam_roles.worker_role_arn`, "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"),
+				VerifyJQ(`.aws.sts.role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"),
+				VerifyJQ(`.aws.sts.support_role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.master_role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.worker_role_arn`, "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"),
 				VerifyJQ(`.aws.sts.operator_role_prefix`, "terraform-operator"),
 				VerifyJQ(`.aws.sts.oidc_endpoint_url`, "https://oidc_endpoint_url"),
-				VerifyJQ(`.aws.sts.oidc_private_key_secret_arn`, "arn:aws:secretsmanager:us-east-1"+
-					":765374464689:secret:oidc-u2u1-6GYVrU"),
+				VerifyJQ(`.aws.sts.oidc_config.managed`, false),
+				VerifyJQ(`.aws.sts.oidc_config.issuer_url`, "https://oidc_endpoint_url"),
+				VerifyJQ(`.aws.sts.oidc_config.secret_arn`, "arn:aws:secretsmanager:us-east-1"+
+					":123456789012:secret:oidc-u2u1-6GYVrU"),
 				RespondWithPatchedJSON(http.StatusOK, template, `[
 					{
 					  "op": "add",
@@ -705,11 +1380,11 @@ var _ = Describe("Cluster creation", func() {
 						  "sts" : {
 							  "oidc_endpoint_url": "https://oidc_endpoint_url",
 							  "thumbprint": "111111",
-							  "role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-							  "support_role_arn": "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 							  "instance_iam_roles" : {
-								"master_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-								"worker_role_arn" : "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 							  },
 							  "operator_role_prefix" : "terraform-operator"
 						  }
@@ -733,21 +1408,1114 @@ var _ = Describe("Cluster creation", func() {
 		resource "ocm_cluster_rosa_classic" "my_cluster" {
 			name           = "my-cluster"
 			cloud_region   = "us-west-1"
-			aws_account_id = "123"
+			aws_account_id = "123456789012"
 			sts = {
-				role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Installer-Role",
-				support_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Support-Role",
+				role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
 				instance_iam_roles = {
-				  master_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-ControlPlane-Role",
-				  worker_role_arn = "arn:aws:iam::account-id:role/ManagedOpenShift-Worker-Role"
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
 				},
 				"operator_role_prefix" : "terraform-operator",
 				"oidc_endpoint_url" : "oidc_endpoint_url",
-				"oidc_private_key_secret_arn" : "arn:aws:secretsmanager:us-east-1:765374464689:secret:oidc-u2u1-6GYVrU"
+				"oidc_private_key_secret_arn" : "arn:aws:secretsmanager:us-east-1:123456789012:secret:oidc-u2u1-6GYVrU"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+	})
+
+	It("Creates rosa sts cluster with an OIDC configuration identifier", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.aws.sts.oidc_config.id`, "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"),
+				VerifyJQ(`.aws.sts.oidc_endpoint_url`, nil),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+					{
+					  "op": "add",
+					  "path": "/aws",
+					  "value": {
+						  "sts" : {
+							  "oidc_endpoint_url": "https://oidc.example.com/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+							  "oidc_config": {
+								  "id": "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+								  "managed": true
+							  },
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+							  "instance_iam_roles" : {
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+							  },
+							  "operator_role_prefix" : "terraform-operator"
+						  }
+					  }
+					}
+				  ]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			sts = {
+				account_role_prefix = "ManagedOpenShift"
+				operator_role_prefix = "terraform-operator"
+				oidc_config_id = "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.sts.oidc_config_id", "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"))
+		Expect(resource).To(MatchJQ(".attributes.sts.oidc_endpoint_url",
+			"oidc.example.com/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"))
+	})
+
+	It("Fails if the OIDC configuration identifier is used with BYO OIDC", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			sts = {
+				account_role_prefix = "ManagedOpenShift"
+				operator_role_prefix = "terraform-operator"
+				oidc_config_id = "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"
+				oidc_endpoint_url = "oidc_endpoint_url"
+				oidc_private_key_secret_arn = "arn:aws:secretsmanager:us-east-1:123456789012:secret:oidc-u2u1-6GYVrU"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Creates rosa sts cluster with account roles derived from the prefix", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.aws.sts.role_arn`, "arn:aws:iam::123456789012:role/rosa/MyPrefix-Installer-Role"),
+				VerifyJQ(`.aws.sts.support_role_arn`, "arn:aws:iam::123456789012:role/MySupport-Role"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.master_role_arn`, "arn:aws:iam::123456789012:role/rosa/MyPrefix-ControlPlane-Role"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.worker_role_arn`, "arn:aws:iam::123456789012:role/rosa/MyPrefix-Worker-Role"),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+					{
+					  "op": "add",
+					  "path": "/aws",
+					  "value": {
+						  "sts" : {
+							  "oidc_endpoint_url": "https://oidc_endpoint_url",
+							  "thumbprint": "111111",
+							  "role_arn": "arn:aws:iam::123456789012:role/rosa/MyPrefix-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/MySupport-Role",
+							  "instance_iam_roles" : {
+								"master_role_arn" : "arn:aws:iam::123456789012:role/rosa/MyPrefix-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/rosa/MyPrefix-Worker-Role"
+							  },
+							  "operator_role_prefix" : "terraform-operator"
+						  }
+					  }
+					}
+				  ]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			sts = {
+				account_role_prefix = "MyPrefix"
+				path = "/rosa/"
+				support_role_arn = "arn:aws:iam::123456789012:role/MySupport-Role"
+				operator_role_prefix = "terraform-operator"
 			}
 		  }
 		`)
 		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.sts.role_arn",
+			"arn:aws:iam::123456789012:role/rosa/MyPrefix-Installer-Role"))
+		Expect(resource).To(MatchJQ(".attributes.sts.support_role_arn",
+			"arn:aws:iam::123456789012:role/MySupport-Role"))
+		Expect(resource).To(MatchJQ(".attributes.sts.instance_iam_roles.master_role_arn",
+			"arn:aws:iam::123456789012:role/rosa/MyPrefix-ControlPlane-Role"))
+		Expect(resource).To(MatchJQ(".attributes.sts.instance_iam_roles.worker_role_arn",
+			"arn:aws:iam::123456789012:role/rosa/MyPrefix-Worker-Role"))
+	})
+
+	It("Creates rosa sts cluster with audit log forwarding and updates it", func() {
+		// awsPatch returns the patch that adds to the cluster template the STS settings and
+		// the given audit log settings:
+		awsPatch := func(auditLog string) string {
+			return `[
+				{
+				  "op": "add",
+				  "path": "/aws",
+				  "value": {
+					  ` + auditLog + `
+					  "sts" : {
+						  "oidc_endpoint_url": "https://oidc_endpoint_url",
+						  "thumbprint": "111111",
+						  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+						  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+						  "instance_iam_roles" : {
+							"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+							"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+						  },
+						  "operator_role_prefix" : "terraform-operator"
+					  }
+				  }
+				}
+			  ]`
+		}
+		source := func(auditLog string) string {
+			return `
+			resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123456789012"
+				` + auditLog + `
+				sts = {
+					account_role_prefix = "ManagedOpenShift"
+					operator_role_prefix = "terraform-operator"
+				}
+			  }
+			`
+		}
+		firstRole := `"audit_log": {"role_arn": "arn:aws:iam::123456789012:role/first-audit-log-role"},`
+		secondRole := `"audit_log": {"role_arn": "arn:aws:iam::123456789012:role/second-audit-log-role"},`
+
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.aws.audit_log.role_arn`, "arn:aws:iam::123456789012:role/first-audit-log-role"),
+				RespondWithPatchedJSON(http.StatusOK, template, awsPatch(firstRole)),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(source(
+			`audit_log_arn = "arn:aws:iam::123456789012:role/first-audit-log-role"`,
+		))
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.audit_log_arn",
+			"arn:aws:iam::123456789012:role/first-audit-log-role"))
+
+		// Change the role:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, awsPatch(firstRole)),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				VerifyJQ(`.aws.audit_log.role_arn`, "arn:aws:iam::123456789012:role/second-audit-log-role"),
+				RespondWithPatchedJSON(http.StatusOK, template, awsPatch(secondRole)),
+			),
+		)
+		terraform.Source(source(
+			`audit_log_arn = "arn:aws:iam::123456789012:role/second-audit-log-role"`,
+		))
+		Expect(terraform.Apply()).To(BeZero())
+		resource = terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.audit_log_arn",
+			"arn:aws:iam::123456789012:role/second-audit-log-role"))
+
+		// Remove the role, which disables the forwarding:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, awsPatch(secondRole)),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				VerifyJQ(`.aws.audit_log.role_arn`, ""),
+				RespondWithPatchedJSON(http.StatusOK, template, awsPatch("")),
+			),
+		)
+		terraform.Source(source(""))
+		Expect(terraform.Apply()).To(BeZero())
+		resource = terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.audit_log_arn", nil))
+	})
+
+	It("Fails if the audit log ARN isn't a role", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			audit_log_arn  = "arn:aws:logs:us-west-1:123456789012:log-group:my-group"
+			sts = {
+				account_role_prefix = "ManagedOpenShift"
+				operator_role_prefix = "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	Context("Test update of the default machine pool", func() {
+		// clusterPatch returns the patch that adds to the cluster template the STS settings and
+		// the given nodes:
+		clusterPatch := func(nodes string) string {
+			return `[
+				{
+				  "op": "add",
+				  "path": "/aws",
+				  "value": {
+					  "sts" : {
+						  "oidc_endpoint_url": "https://oidc_endpoint_url",
+						  "thumbprint": "111111",
+						  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+						  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+						  "instance_iam_roles" : {
+							"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+							"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+						  },
+						  "operator_role_prefix" : "terraform-operator"
+					  }
+				  }
+				},
+				{
+				  "op": "add",
+				  "path": "/nodes",
+				  "value": ` + nodes + `
+				}
+			  ]`
+		}
+		source := func(pool string) string {
+			return `
+			resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123456789012"
+				` + pool + `
+				sts = {
+					account_role_prefix = "ManagedOpenShift"
+					operator_role_prefix = "terraform-operator"
+				}
+			  }
+			`
+		}
+
+		It("Changes and removes the compute labels", func() {
+			// Create the cluster:
+			labeled := `{"compute": 2, "compute_labels": {"role": "web"}}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyJQ(`.nodes.compute`, float64(2)),
+					VerifyJQ(`.nodes.compute_labels.role`, "web"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(labeled)),
+				),
+			)
+			terraform.Source(source(`
+				replicas = 2
+				compute_labels = {
+					"role" = "web"
+				}
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Change the labels, the number of replicas shouldn't be sent:
+			relabeled := `{"compute": 2, "compute_labels": {"role": "db", "tier": "backend"}}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(labeled)),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.nodes.compute`, nil),
+					VerifyJQ(`.nodes.autoscale_compute`, nil),
+					VerifyJQ(`.nodes.compute_labels`, map[string]interface{}{
+						"role": "db",
+						"tier": "backend",
+					}),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(relabeled)),
+				),
+			)
+			terraform.Source(source(`
+				replicas = 2
+				compute_labels = {
+					"role" = "db"
+					"tier" = "backend"
+				}
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.compute_labels.tier`, "backend"))
+
+			// Remove the labels, an empty map should be sent:
+			unlabeled := `{"compute": 2}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(relabeled)),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.nodes.compute_labels`, map[string]interface{}{}),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(unlabeled)),
+				),
+			)
+			terraform.Source(source(`replicas = 2`))
+			Expect(terraform.Apply()).To(BeZero())
+			resource = terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.compute_labels`, nil))
+		})
+
+		It("Changes the replicas and switches to autoscaling and back", func() {
+			// Create the cluster:
+			fixed := `{"compute": 2}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyJQ(`.nodes.compute`, float64(2)),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(fixed)),
+				),
+			)
+			terraform.Source(source(`replicas = 2`))
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Change the number of replicas:
+			scaled := `{"compute": 3}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(fixed)),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.nodes.compute`, float64(3)),
+					VerifyJQ(`.nodes.compute_labels`, nil),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(scaled)),
+				),
+			)
+			terraform.Source(source(`replicas = 3`))
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.replicas`, 3.0))
+
+			// Enable autoscaling, both limits should be sent and the replicas shouldn't:
+			autoscaled := `{"compute": 3, "autoscale_compute": {"min_replicas": 2, "max_replicas": 5}}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(scaled)),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.nodes.compute`, nil),
+					VerifyJQ(`.nodes.autoscale_compute.min_replicas`, float64(2)),
+					VerifyJQ(`.nodes.autoscale_compute.max_replicas`, float64(5)),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(autoscaled)),
+				),
+			)
+			terraform.Source(source(`
+				autoscaling_enabled = true
+				min_replicas = 2
+				max_replicas = 5
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+			resource = terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.autoscaling_enabled`, true))
+			Expect(resource).To(MatchJQ(`.attributes.max_replicas`, 5.0))
+
+			// Change only the maximum, both limits should be sent:
+			widened := `{"compute": 3, "autoscale_compute": {"min_replicas": 2, "max_replicas": 8}}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(autoscaled)),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.nodes.autoscale_compute.min_replicas`, float64(2)),
+					VerifyJQ(`.nodes.autoscale_compute.max_replicas`, float64(8)),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(widened)),
+				),
+			)
+			terraform.Source(source(`
+				autoscaling_enabled = true
+				min_replicas = 2
+				max_replicas = 8
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Disable autoscaling, the number of replicas should be sent even if it didn't
+			// change:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(widened)),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.nodes.compute`, float64(3)),
+					VerifyJQ(`.nodes.autoscale_compute`, nil),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(scaled)),
+				),
+			)
+			terraform.Source(source(`replicas = 3`))
+			Expect(terraform.Apply()).To(BeZero())
+			resource = terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.autoscaling_enabled`, nil))
+			Expect(resource).To(MatchJQ(`.attributes.min_replicas`, nil))
+			Expect(resource).To(MatchJQ(`.attributes.replicas`, 3.0))
+		})
+
+		It("Fails to disable autoscaling without the number of replicas", func() {
+			// Create the cluster:
+			autoscaled := `{"autoscale_compute": {"min_replicas": 2, "max_replicas": 5}}`
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(autoscaled)),
+				),
+			)
+			terraform.Source(source(`
+				autoscaling_enabled = true
+				min_replicas = 2
+				max_replicas = 5
+			`))
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Disable autoscaling, the cluster should not be patched:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, clusterPatch(autoscaled)),
+				),
+			)
+			terraform.Source(source(`autoscaling_enabled = false`))
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+
+		It("Fails if the replicas are used with autoscaling", func() {
+			// Run the apply command, the cluster should not be requested:
+			terraform.Source(source(`
+				autoscaling_enabled = true
+				replicas = 3
+				min_replicas = 2
+				max_replicas = 5
+			`))
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+
+		It("Fails if autoscaling limits are used without autoscaling", func() {
+			// Run the apply command, the cluster should not be requested:
+			terraform.Source(source(`
+				replicas = 3
+				min_replicas = 2
+			`))
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+
+		It("Fails if autoscaling is enabled without the maximum replicas", func() {
+			// Run the apply command, the cluster should not be requested:
+			terraform.Source(source(`
+				autoscaling_enabled = true
+				min_replicas = 2
+			`))
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+	})
+
+	It("Creates rosa cluster with worker disk size and IMDSv2 required", func() {
+		// The cluster returned by the server:
+		const patch = `[
+			{
+			  "op": "add",
+			  "path": "/aws",
+			  "value": {
+				  "ec2_metadata_http_tokens": "required",
+				  "sts" : {
+					  "oidc_endpoint_url": "https://oidc_endpoint_url",
+					  "thumbprint": "111111",
+					  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+					  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+					  "instance_iam_roles" : {
+						"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+						"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+					  },
+					  "operator_role_prefix" : "terraform-operator"
+				  }
+			  }
+			},
+			{
+			  "op": "add",
+			  "path": "/nodes",
+			  "value": {
+				"compute": 3,
+				"compute_machine_type": {
+					"id": "r5.xlarge"
+				},
+				"compute_root_volume": {
+					"aws": {
+						"size": 400
+					}
+				}
+			  }
+			}
+		  ]`
+		source := func(diskSize int) string {
+			return fmt.Sprintf(`
+			resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name                     = "my-cluster"
+				cloud_region             = "us-west-1"
+				aws_account_id           = "123456789012"
+				worker_disk_size         = %d
+				ec2_metadata_http_tokens = "required"
+				sts = {
+					account_role_prefix = "ManagedOpenShift"
+					operator_role_prefix = "terraform-operator"
+				}
+			  }
+			`, diskSize)
+		}
+
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.nodes.compute_root_volume.aws.size`, float64(400)),
+				VerifyJQ(`.aws.ec2_metadata_http_tokens`, "required"),
+				RespondWithPatchedJSON(http.StatusOK, template, patch),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(source(400))
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.worker_disk_size", 400.0))
+		Expect(resource).To(MatchJQ(".attributes.ec2_metadata_http_tokens", "required"))
+
+		// Changing the disk size should fail without sending the patch:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, patch),
+			),
+		)
+		terraform.Source(source(500))
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the worker disk size is too small", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name             = "my-cluster"
+			cloud_region     = "us-west-1"
+			aws_account_id   = "123456789012"
+			worker_disk_size = 64
+			sts = {
+				account_role_prefix = "ManagedOpenShift"
+				operator_role_prefix = "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the IMDS setting isn't valid", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name                     = "my-cluster"
+			cloud_region             = "us-west-1"
+			aws_account_id           = "123456789012"
+			ec2_metadata_http_tokens = "v2only"
+			sts = {
+				account_role_prefix = "ManagedOpenShift"
+				operator_role_prefix = "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Creates rosa cluster with additional security groups", func() {
+		// The cluster returned by the server:
+		const patch = `[
+			{
+			  "op": "add",
+			  "path": "/aws",
+			  "value": {
+				  "subnet_ids": ["subnet-1", "subnet-2"],
+				  "additional_control_plane_security_group_ids": ["sg-0000000a"],
+				  "additional_infra_security_group_ids": ["sg-0000000b"],
+				  "additional_compute_security_group_ids": ["sg-0000000c", "sg-0123456789abcdef0"],
+				  "sts" : {
+					  "oidc_endpoint_url": "https://oidc_endpoint_url",
+					  "thumbprint": "111111",
+					  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+					  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+					  "instance_iam_roles" : {
+						"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+						"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+					  },
+					  "operator_role_prefix" : "terraform-operator"
+				  }
+			  }
+			}
+		  ]`
+		source := func(computeGroups string) string {
+			return `
+			resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123456789012"
+				aws_subnet_ids = ["subnet-1", "subnet-2"]
+				additional_control_plane_security_group_ids = ["sg-0000000a"]
+				additional_infra_security_group_ids = ["sg-0000000b"]
+				additional_compute_security_group_ids = ` + computeGroups + `
+				sts = {
+					account_role_prefix = "ManagedOpenShift"
+					operator_role_prefix = "terraform-operator"
+				}
+			  }
+			`
+		}
+
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.aws.additional_control_plane_security_group_ids`, []interface{}{"sg-0000000a"}),
+				VerifyJQ(`.aws.additional_infra_security_group_ids`, []interface{}{"sg-0000000b"}),
+				VerifyJQ(`.aws.additional_compute_security_group_ids`, []interface{}{
+					"sg-0000000c", "sg-0123456789abcdef0",
+				}),
+				RespondWithPatchedJSON(http.StatusOK, template, patch),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(source(`["sg-0000000c", "sg-0123456789abcdef0"]`))
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.additional_control_plane_security_group_ids.[0]",
+			"sg-0000000a"))
+		Expect(resource).To(MatchJQ(".attributes.additional_compute_security_group_ids|length", 2))
+
+		// Changing the security groups should fail without sending the patch:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, patch),
+			),
+		)
+		terraform.Source(source(`["sg-0000000c"]`))
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the security groups are used without subnets", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			additional_compute_security_group_ids = ["sg-0000000c"]
+			sts = {
+				account_role_prefix = "ManagedOpenShift"
+				operator_role_prefix = "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if a security group identifier isn't valid", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			aws_subnet_ids = ["subnet-1", "subnet-2"]
+			additional_infra_security_group_ids = ["my-group"]
+			sts = {
+				account_role_prefix = "ManagedOpenShift"
+				operator_role_prefix = "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Creates cluster with a generated admin password", func() {
+		// The cluster returned by the server:
+		const patch = `[
+			{
+			  "op": "add",
+			  "path": "/nodes",
+			  "value": {
+				"compute": 3,
+				"compute_machine_type": {
+					"id": "r5.xlarge"
+				}
+			  }
+			}
+		  ]`
+
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.htpasswd.users.items | length`, 1),
+				VerifyJQ(`.htpasswd.users.items[0].username`, "cluster-admin"),
+				VerifyJQ(`.htpasswd.users.items[0].password | length`, 23),
+				RespondWithPatchedJSON(http.StatusCreated, template, patch),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name              = "my-cluster"
+		    cloud_region      = "us-west-1"
+		    aws_account_id    = "123"
+		    admin_credentials = {}
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.admin_credentials.username", "cluster-admin"))
+		Expect(resource).To(MatchJQ(".attributes.admin_credentials.password | length", 23))
+
+		// Applying again should keep the generated password, so the cluster isn't updated:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, patch),
+			),
+		)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Removing the credentials should fail without sending the patch:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, patch),
+			),
+		)
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+		    aws_account_id = "123"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Creates cluster with the given admin credentials", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.htpasswd.users.items[0].username`, "my-admin"),
+				VerifyJQ(`.htpasswd.users.items[0].password`, "Ch4ngeMe-Please"),
+				RespondWithPatchedJSON(http.StatusCreated, template, `[
+					{
+					  "op": "add",
+					  "path": "/nodes",
+					  "value": {
+						"compute": 3,
+						"compute_machine_type": {
+							"id": "r5.xlarge"
+						}
+					  }
+					}]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+		    aws_account_id = "123"
+		    admin_credentials = {
+		      username = "my-admin"
+		      password = "Ch4ngeMe-Please"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.admin_credentials.username", "my-admin"))
+		Expect(resource).To(MatchJQ(".attributes.admin_credentials.password", "Ch4ngeMe-Please"))
+	})
+
+	It("Fails if the admin password is too weak", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+		    aws_account_id = "123"
+		    admin_credentials = {
+		      password = "changeme"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the account roles are neither set nor derived", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			sts = {
+				role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+				operator_role_prefix = "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the BYO OIDC secret ARN is missing", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			sts = {
+				role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+				instance_iam_roles = {
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+				},
+				"operator_role_prefix" : "terraform-operator",
+				"oidc_endpoint_url" : "oidc_endpoint_url"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if a role belongs to a different AWS account", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			sts = {
+				role_arn = "arn:aws:iam::210987654321:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+				instance_iam_roles = {
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+				},
+				"operator_role_prefix" : "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if a role ARN isn't valid", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			sts = {
+				role_arn = "ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+				instance_iam_roles = {
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+				},
+				"operator_role_prefix" : "terraform-operator"
+			}
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Writes the STS cleanup report when the cluster is destroyed", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithPatchedJSON(http.StatusOK, templateReadyState, `[
+					{
+					  "op": "add",
+					  "path": "/aws",
+					  "value": {
+						  "sts" : {
+							  "oidc_endpoint_url": "https://oidc_endpoint_url",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+							  "instance_iam_roles" : {
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+							  },
+							  "operator_role_prefix" : "terraform-operator",
+							  "operator_iam_roles" : [
+								{
+								  "name": "cloud-credentials",
+								  "namespace": "openshift-ingress-operator",
+								  "role_arn": "arn:aws:iam::123456789012:role/terraform-operator-openshift-ingress-operator-cloud-credentials"
+								},
+								{
+								  "name": "ebs-cloud-credentials",
+								  "namespace": "openshift-cluster-csi-drivers",
+								  "role_arn": "arn:aws:iam::123456789012:role/terraform-operator-openshift-cluster-csi-drivers-ebs-cloud-credent"
+								}
+							  ]
+						  }
+					  }
+					}
+				  ]`),
+			),
+		)
+
+		// Run the apply command:
+		reportDir, err := ioutil.TempDir("", "ocm-test-*.d")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(reportDir)
+		reportFile := filepath.Join(reportDir, "report.json")
+		terraform.Source(EvaluateTemplate(`
+		resource "ocm_cluster_rosa_classic" "my_cluster" {
+			name           = "my-cluster"
+			cloud_region   = "us-west-1"
+			aws_account_id = "123456789012"
+			disable_waiting_in_destroy = true
+			sts_cleanup_report_file = "{{ .ReportFile }}"
+			sts = {
+				role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				support_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+				instance_iam_roles = {
+				  master_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+				  worker_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+				},
+				"operator_role_prefix" : "terraform-operator"
+			}
+		  }
+		`, "ReportFile", strings.ReplaceAll(reportFile, "\\", "/")))
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Run the destroy command:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, templateReadyState, `[
+					{
+					  "op": "add",
+					  "path": "/aws",
+					  "value": {
+						  "sts" : {
+							  "oidc_endpoint_url": "https://oidc_endpoint_url",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+							  "instance_iam_roles" : {
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+							  },
+							  "operator_role_prefix" : "terraform-operator",
+							  "operator_iam_roles" : [
+								{
+								  "name": "cloud-credentials",
+								  "namespace": "openshift-ingress-operator",
+								  "role_arn": "arn:aws:iam::123456789012:role/terraform-operator-openshift-ingress-operator-cloud-credentials"
+								},
+								{
+								  "name": "ebs-cloud-credentials",
+								  "namespace": "openshift-cluster-csi-drivers",
+								  "role_arn": "arn:aws:iam::123456789012:role/terraform-operator-openshift-cluster-csi-drivers-ebs-cloud-credent"
+								}
+							  ]
+						  }
+					  }
+					}
+				  ]`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, templateReadyState, `[
+					{
+					  "op": "add",
+					  "path": "/aws",
+					  "value": {
+						  "sts" : {
+							  "oidc_endpoint_url": "https://oidc_endpoint_url",
+							  "role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+							  "support_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Support-Role",
+							  "instance_iam_roles" : {
+								"master_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-ControlPlane-Role",
+								"worker_role_arn" : "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+							  },
+							  "operator_role_prefix" : "terraform-operator",
+							  "operator_iam_roles" : [
+								{
+								  "name": "cloud-credentials",
+								  "namespace": "openshift-ingress-operator",
+								  "role_arn": "arn:aws:iam::123456789012:role/terraform-operator-openshift-ingress-operator-cloud-credentials"
+								},
+								{
+								  "name": "ebs-cloud-credentials",
+								  "namespace": "openshift-cluster-csi-drivers",
+								  "role_arn": "arn:aws:iam::123456789012:role/terraform-operator-openshift-cluster-csi-drivers-ebs-cloud-credent"
+								}
+							  ]
+						  }
+					  }
+					}
+				  ]`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, templateReadyState),
+			),
+		)
+		Expect(terraform.Destroy()).To(BeZero())
+
+		// Check the report:
+		data, err := ioutil.ReadFile(reportFile)
+		Expect(err).ToNot(HaveOccurred())
+		var report interface{}
+		err = json.Unmarshal(data, &report)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report).To(MatchJQ(".cluster_id", "123"))
+		Expect(report).To(MatchJQ(".oidc_endpoint_url", "https://oidc_endpoint_url"))
+		Expect(report).To(MatchJQ(".operator_role_arns[0]",
+			"arn:aws:iam::123456789012:role/terraform-operator-openshift-ingress-operator-cloud-credentials"))
+		Expect(report).To(MatchJQ(".operator_role_arns[1]",
+			"arn:aws:iam::123456789012:role/terraform-operator-openshift-cluster-csi-drivers-ebs-cloud-credent"))
 	})
 
 })
@@ -26,6 +26,28 @@ import (
 )
 
 var _ = Describe("Cluster creation", func() {
+	// This is the list of versions that will be returned by the server when the version of the
+	// cluster is validated.
+	const versions = `{
+	  "page": 1,
+	  "size": 2,
+	  "total": 2,
+	  "items": [
+	    {
+	      "id": "openshift-v4.8.1",
+	      "raw_id": "4.8.1",
+	      "enabled": true,
+	      "channel_group": "stable"
+	    },
+	    {
+	      "id": "openshift-v4.8.2",
+	      "raw_id": "4.8.2",
+	      "enabled": true,
+	      "channel_group": "stable"
+	    }
+	  ]
+	}`
+
 	// This is the cluster that will be returned by the server when asked to create or retrieve
 	// a cluster.
 	const template = `{
@@ -210,7 +232,7 @@ var _ = Describe("Cluster creation", func() {
 				VerifyJQ(".network.machine_cidr", "10.0.0.0/15"),
 				VerifyJQ(".network.service_cidr", "172.30.0.0/15"),
 				VerifyJQ(".network.pod_cidr", "10.128.0.0/13"),
-				VerifyJQ(".network.host_prefix", 22.0),
+				VerifyJQ(".network.host_prefix", 24.0),
 				RespondWithPatchedJSON(http.StatusOK, template, `[
 				  {
 				    "op": "replace",
@@ -219,7 +241,7 @@ var _ = Describe("Cluster creation", func() {
 				      "machine_cidr": "10.0.0.0/15",
 				      "service_cidr": "172.30.0.0/15",
 				      "pod_cidr": "10.128.0.0/13",
-				      "host_prefix": 22
+				      "host_prefix": 24
 				    }
 				  }
 				]`),
@@ -236,7 +258,7 @@ var _ = Describe("Cluster creation", func() {
 		    machine_cidr   = "10.0.0.0/15"
 		    service_cidr   = "172.30.0.0/15"
 		    pod_cidr       = "10.128.0.0/13"
-		    host_prefix    = 22
+		    host_prefix    = 24
 		  }
 		`)
 		Expect(terraform.Apply()).To(BeZero())
@@ -246,15 +268,300 @@ var _ = Describe("Cluster creation", func() {
 		Expect(resource).To(MatchJQ(".attributes.machine_cidr", "10.0.0.0/15"))
 		Expect(resource).To(MatchJQ(".attributes.service_cidr", "172.30.0.0/15"))
 		Expect(resource).To(MatchJQ(".attributes.pod_cidr", "10.128.0.0/13"))
-		Expect(resource).To(MatchJQ(".attributes.host_prefix", 22.0))
+		Expect(resource).To(MatchJQ(".attributes.host_prefix", 24.0))
+	})
+
+	It("Fails if the CIDR blocks overlap", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    machine_cidr   = "10.0.0.0/16"
+		    service_cidr   = "10.0.128.0/17"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the host prefix is out of range", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    host_prefix    = 22
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the pod CIDR is too small for the compute nodes", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    pod_cidr       = "10.128.0.0/20"
+		    host_prefix    = 23
+		    compute_nodes  = 10
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Creates cluster with admin credentials", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.htpasswd.users.items[0].username`, "my-admin"),
+				VerifyJQ(`.htpasswd.users.items[0].password | length`, 23),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    admin_credentials = {
+		      username = "my-admin"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.admin_credentials.username", "my-admin"))
+		Expect(resource).To(MatchJQ(".attributes.admin_credentials.password | length", 23))
+	})
+
+	It("Fails if the admin user name isn't valid", func() {
+		// Run the apply command, the cluster should not be requested:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    admin_credentials = {
+		      username = "my:admin"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the admin password is changed", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    admin_credentials = {
+		      username = "my-admin"
+		      password = "Adm1nPassw0rd123"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server for the refresh, the cluster should not be replaced:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply command again with a different password:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    admin_credentials = {
+		      username = "my-admin"
+		      password = "0therPassw0rd123"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Accepts admin credentials missing from the state if the cluster has the admin user", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command without the credentials, like the state of an imported
+		// cluster:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server for the apply, the credentials shouldn't be sent:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/clusters/123/identity_providers",
+			RespondWithJSON(http.StatusOK, `{
+			  "page": 1,
+			  "size": 1,
+			  "total": 1,
+			  "items": [
+			    {
+			      "id": "456",
+			      "name": "cluster-admin",
+			      "type": "HTPasswdIdentityProvider"
+			    }
+			  ]
+			}`),
+		)
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users",
+			RespondWithJSON(http.StatusOK, `{
+			  "page": 1,
+			  "size": 1,
+			  "total": 1,
+			  "items": [
+			    {
+			      "id": "789",
+			      "username": "my-admin"
+			    }
+			  ]
+			}`),
+		)
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				VerifyJQ(`has("htpasswd")`, false),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply command with the credentials:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    admin_credentials = {
+		      username = "my-admin"
+		      password = "Adm1nPassw0rd123"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.admin_credentials.username", "my-admin"))
+	})
+
+	It("Fails to add admin credentials if the cluster doesn't have the admin user", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command without the credentials:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server for the apply, the cluster shouldn't be updated:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 0,
+				  "total": 0,
+				  "items": []
+				}`),
+			),
+		)
+
+		// Run the apply command with the credentials:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    admin_credentials = {
+		      username = "my-admin"
+		      password = "Adm1nPassw0rd123"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
 	})
 
 	It("Sets version", func() {
 		// Prepare the server:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/versions",
+			CombineHandlers(
+				VerifyFormKV("search", "enabled = 't' and channel_group = 'stable'"),
+				RespondWithJSON(http.StatusOK, versions),
+			),
+		)
 		server.AppendHandlers(
 			CombineHandlers(
 				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
 				VerifyJQ(".version.id", "openshift-v4.8.1"),
+				VerifyJQ(".version.channel_group", "stable"),
 				RespondWithPatchedJSON(http.StatusOK, template, `[
 				  {
 				    "op": "replace",
@@ -284,6 +591,489 @@ var _ = Describe("Cluster creation", func() {
 		Expect(resource).To(MatchJQ(".attributes.version", "openshift-v4.8.1"))
 	})
 
+	It("Sets version of the candidate channel group", func() {
+		// Prepare the server:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/versions",
+			CombineHandlers(
+				VerifyFormKV("search", "enabled = 't' and channel_group = 'candidate'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 1,
+				  "total": 1,
+				  "items": [
+				    {
+				      "id": "openshift-v4.9.0-fc.1-candidate",
+				      "raw_id": "4.9.0-fc.1",
+				      "enabled": true,
+				      "channel_group": "candidate"
+				    }
+				  ]
+				}`),
+			),
+		)
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(".version.id", "openshift-v4.9.0-fc.1-candidate"),
+				VerifyJQ(".version.channel_group", "candidate"),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+				  {
+				    "op": "replace",
+				    "path": "/version",
+				    "value": {
+				      "id": "openshift-v4.9.0-fc.1-candidate",
+				      "channel_group": "candidate"
+				    }
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    version        = "4.9.0-fc.1"
+		    channel_group  = "candidate"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.version", "4.9.0-fc.1"))
+		Expect(resource).To(MatchJQ(".attributes.channel_group", "candidate"))
+	})
+
+	It("Adds the channel group suffix to versions with the prefix", func() {
+		// Prepare the server:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/versions",
+			CombineHandlers(
+				VerifyFormKV("search", "enabled = 't' and channel_group = 'candidate'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 1,
+				  "total": 1,
+				  "items": [
+				    {
+				      "id": "openshift-v4.9.0-fc.1-candidate",
+				      "raw_id": "4.9.0-fc.1",
+				      "enabled": true,
+				      "channel_group": "candidate"
+				    }
+				  ]
+				}`),
+			),
+		)
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(".version.id", "openshift-v4.9.0-fc.1-candidate"),
+				VerifyJQ(".version.channel_group", "candidate"),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+				  {
+				    "op": "replace",
+				    "path": "/version",
+				    "value": {
+				      "id": "openshift-v4.9.0-fc.1-candidate",
+				      "channel_group": "candidate"
+				    }
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    version        = "openshift-v4.9.0-fc.1"
+		    channel_group  = "candidate"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.version", "openshift-v4.9.0-fc.1"))
+		Expect(resource).To(MatchJQ(".attributes.channel_group", "candidate"))
+	})
+
+	It("Fails if the version isn't enabled", func() {
+		// Prepare the server, the cluster shouldn't be created:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/versions",
+			RespondWithJSON(http.StatusOK, versions),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    version        = "openshift-v4.8.5"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the version has the suffix of another channel group", func() {
+		// Run the apply command, neither the versions nor the cluster should be requested:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    version        = "openshift-v4.8.1-fast"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the channel group isn't valid", func() {
+		// Run the apply command, neither the versions nor the cluster should be requested:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    version        = "openshift-v4.8.1"
+		    channel_group  = "beta"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Imports a cluster by name", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("search", "name = 'my-cluster'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 1,
+				  "total": 1,
+				  "items": [
+				    {
+				      "id": "123",
+				      "name": "my-cluster"
+				    }
+				  ]
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Import("ocm_cluster.my_cluster", "name:my-cluster")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.id", "123"))
+		Expect(resource).To(MatchJQ(".attributes.deletion_protection", nil))
+	})
+
+	It("Imports the delete protection of the cluster", func() {
+		// Prepare the server:
+		protected := `[
+		  {
+		    "op": "add",
+		    "path": "/delete_protection",
+		    "value": {
+		      "enabled": true
+		    }
+		  }
+		]`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, protected),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, protected),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name                = "my-cluster"
+		    product             = "osd"
+		    cloud_provider      = "aws"
+		    cloud_region        = "us-west-1"
+		    deletion_protection = true
+		  }
+		`)
+		Expect(terraform.Import("ocm_cluster.my_cluster", "123")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.deletion_protection", true))
+	})
+
+	It("Fails to import if the name is ambiguous", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("search", "name = 'my-cluster'"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 2,
+				  "total": 2,
+				  "items": [
+				    {
+				      "id": "123",
+				      "name": "my-cluster"
+				    },
+				    {
+				      "id": "456",
+				      "name": "my-cluster"
+				    }
+				  ]
+				}`),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+			product		   = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Import("ocm_cluster.my_cluster", "name:my-cluster")).ToNot(BeZero())
+	})
+
+	It("Doesn't delete a cluster with deletion protection", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.delete_protection.enabled`, true),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply and destroy commands:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name                = "my-cluster"
+			product		        = "osd"
+		    cloud_provider      = "aws"
+		    cloud_region        = "us-west-1"
+		    deletion_protection = true
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		Expect(terraform.Destroy()).ToNot(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.deletion_protection", true))
+	})
+
+	It("Enables the delete protection when deletion protection is set", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`has("delete_protection")`, false),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/delete_protection"),
+				VerifyJQ(`.enabled`, true),
+				RespondWithJSON(http.StatusOK, `{
+				  "enabled": true
+				}`),
+			),
+		)
+
+		// Run the apply command enabling the protection:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name                = "my-cluster"
+		    product             = "osd"
+		    cloud_provider      = "aws"
+		    cloud_region        = "us-west-1"
+		    deletion_protection = true
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.deletion_protection", true))
+	})
+
+	It("Disables the delete protection enabled outside of Terraform", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Applying again should detect that the protection has been enabled and disable it
+		// after updating the cluster:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+				  {
+				    "op": "add",
+				    "path": "/delete_protection",
+				    "value": {
+				      "enabled": true
+				    }
+				  }
+				]`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/delete_protection"),
+				VerifyJQ(`.enabled`, false),
+				RespondWithJSON(http.StatusOK, `{
+				  "enabled": false
+				}`),
+			),
+		)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.deletion_protection", nil))
+	})
+
+	It("Doesn't enable the delete protection if the cluster update fails", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server, the delete protection shouldn't be updated:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusBadRequest, `{
+				  "kind": "Error",
+				  "reason": "Cluster can't be updated"
+				}`),
+			),
+		)
+
+		// Run the apply command enabling the protection:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name                = "my-cluster"
+		    product             = "osd"
+		    cloud_provider      = "aws"
+		    cloud_region        = "us-west-1"
+		    deletion_protection = true
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.deletion_protection", nil))
+	})
+
 	It("Fails if the cluster already exists", func() {
 		// Prepare the server:
 		server.AppendHandlers(
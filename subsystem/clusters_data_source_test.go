@@ -0,0 +1,156 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Clusters data source", func() {
+	It("Can search clusters", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("search", "product.id = 'rosa' and region.id = 'us-east-1'"),
+				VerifyFormKV("order", "name asc"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 2,
+				  "total": 2,
+				  "items": [
+				    {
+				      "id": "123",
+				      "name": "my-cluster",
+				      "state": "ready",
+				      "version": {
+				        "id": "openshift-v4.11.1"
+				      },
+				      "region": {
+				        "id": "us-east-1"
+				      },
+				      "product": {
+				        "id": "rosa"
+				      },
+				      "multi_az": true,
+				      "api": {
+				        "url": "https://my-api.example.com"
+				      },
+				      "console": {
+				        "url": "https://my-console.example.com"
+				      }
+				    },
+				    {
+				      "id": "456",
+				      "name": "your-cluster",
+				      "state": "installing",
+				      "version": {
+				        "id": "openshift-v4.11.2"
+				      },
+				      "region": {
+				        "id": "us-east-1"
+				      },
+				      "product": {
+				        "id": "rosa"
+				      },
+				      "multi_az": false
+				    }
+				  ]
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_clusters" "my_clusters" {
+		    search = "product.id = 'rosa' and region.id = 'us-east-1'"
+		    order  = "name asc"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_clusters", "my_clusters")
+		Expect(resource).To(MatchJQ(`.attributes.items | length`, 2))
+		Expect(resource).To(MatchJQ(`.attributes.item`, nil))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].id`, "123"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].name`, "my-cluster"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].state`, "ready"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].version`, "openshift-v4.11.1"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].region`, "us-east-1"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].product`, "rosa"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].multi_az`, true))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].api_url`, "https://my-api.example.com"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].console_url`, "https://my-console.example.com"))
+		Expect(resource).To(MatchJQ(`.attributes.items[1].id`, "456"))
+		Expect(resource).To(MatchJQ(`.attributes.items[1].state`, "installing"))
+		Expect(resource).To(MatchJQ(`.attributes.items[1].multi_az`, false))
+	})
+
+	It("Reads all the pages", func() {
+		// Prepare the server with a first full page and a second page with one item:
+		items := make([]string, 100)
+		for i := range items {
+			items[i] = fmt.Sprintf(`{"id": "%d", "name": "cluster-%d"}`, i, i)
+		}
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("size", "100"),
+				RespondWithJSON(http.StatusOK, fmt.Sprintf(`{
+				  "page": 1,
+				  "size": 100,
+				  "total": 101,
+				  "items": [%s]
+				}`, strings.Join(items, ","))),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("page", "2"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 2,
+				  "size": 1,
+				  "total": 101,
+				  "items": [
+				    {
+				      "id": "100",
+				      "name": "cluster-100"
+				    }
+				  ]
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_clusters" "my_clusters" {
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_clusters", "my_clusters")
+		Expect(resource).To(MatchJQ(`.attributes.items | length`, 101))
+		Expect(resource).To(MatchJQ(`.attributes.items[100].name`, "cluster-100"))
+	})
+})
@@ -78,3 +78,62 @@ var _ = Describe("Group membership creation", func() {
 		Expect(resource).To(MatchJQ(".attributes.user", "my-admin"))
 	})
 })
+
+var _ = Describe("Group membership import", func() {
+	It("Can import a group membership using the cluster, group and user", func() {
+		// Prepare the server, the user is retrieved once to import it and once more to
+		// refresh it:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/groups/dedicated-admins/users/my-admin",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "my-admin"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/groups/dedicated-admins/users/my-admin",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "my-admin"
+				}`),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_group_membership" "my_membership" {
+		    cluster   = "123"
+		    group     = "dedicated-admins"
+		    user      = "my-admin"
+		  }
+		`)
+		Expect(terraform.Import(
+			"ocm_group_membership.my_membership",
+			"123,dedicated-admins,my-admin",
+		)).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_group_membership", "my_membership")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.group", "dedicated-admins"))
+		Expect(resource).To(MatchJQ(".attributes.id", "my-admin"))
+		Expect(resource).To(MatchJQ(".attributes.user", "my-admin"))
+	})
+
+	It("Fails to import a group membership with an empty group", func() {
+		// Run the import command, no request should be sent:
+		terraform.Source(`
+		  resource "ocm_group_membership" "my_membership" {
+		    cluster   = "123"
+		    group     = "dedicated-admins"
+		    user      = "my-admin"
+		  }
+		`)
+		Expect(terraform.Import("ocm_group_membership.my_membership", "123,,my-admin")).ToNot(BeZero())
+	})
+})
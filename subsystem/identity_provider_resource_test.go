@@ -297,3 +297,150 @@ var _ = Describe("Identity provider creation", func() {
 	})
 
 })
+
+var _ = Describe("Identity provider import", func() {
+	// This is the list of identity providers that will be returned by the server:
+	const list = `{
+	  "page": 1,
+	  "size": 2,
+	  "total": 2,
+	  "items": [
+	    {
+	      "id": "456",
+	      "name": "my-ip",
+	      "gitlab": {
+	        "client_id": "test_client",
+	        "url": "https://test.gitlab.com"
+	      }
+	    },
+	    {
+	      "id": "789",
+	      "name": "your-ip",
+	      "htpasswd": {
+	        "username": "my-user"
+	      }
+	    }
+	  ]
+	}`
+
+	It("Can import an identity provider using its name", func() {
+		// Prepare the server, the identity provider is found in the list to import it and
+		// then retrieved to refresh it:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers",
+				),
+				RespondWithJSON(http.StatusOK, list),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "456",
+				  "name": "my-ip",
+				  "gitlab": {
+				    "client_id": "test_client",
+				    "url": "https://test.gitlab.com"
+				  }
+				}`),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    gitlab = {
+		      client_id     = "test_client"
+		      client_secret = "test_secret"
+		      url           = "https://test.gitlab.com"
+		    }
+		  }
+		`)
+		Expect(terraform.Import("ocm_identity_provider.my_ip", "123,my-ip")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_identity_provider", "my_ip")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "456"))
+		Expect(resource).To(MatchJQ(".attributes.name", "my-ip"))
+		Expect(resource).To(MatchJQ(".attributes.gitlab.client_id", "test_client"))
+		Expect(resource).To(MatchJQ(".attributes.gitlab.url", "https://test.gitlab.com"))
+	})
+
+	It("Can import an identity provider using its identifier", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers",
+				),
+				RespondWithJSON(http.StatusOK, list),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/789",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "789",
+				  "name": "your-ip",
+				  "htpasswd": {
+				    "username": "my-user"
+				  }
+				}`),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "your_ip" {
+		    cluster = "123"
+		    name    = "your-ip"
+		    htpasswd = {
+		      username = "my-user"
+		      password = "my-password"
+		    }
+		  }
+		`)
+		Expect(terraform.Import("ocm_identity_provider.your_ip", "123,789")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_identity_provider", "your_ip")
+		Expect(resource).To(MatchJQ(".attributes.id", "789"))
+		Expect(resource).To(MatchJQ(".attributes.name", "your-ip"))
+		Expect(resource).To(MatchJQ(".attributes.htpasswd.username", "my-user"))
+	})
+
+	It("Fails to import an identity provider that doesn't exist", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers",
+				),
+				RespondWithJSON(http.StatusOK, list),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    htpasswd = {
+		      username = "my-user"
+		      password = "my-password"
+		    }
+		  }
+		`)
+		Expect(terraform.Import("ocm_identity_provider.my_ip", "123,other-ip")).ToNot(BeZero())
+	})
+})
@@ -237,3 +237,67 @@ var _ = Describe("Machine pool creation", func() {
 	})
 
 })
+
+var _ = Describe("Machine pool import", func() {
+	It("Can import a machine pool using the cluster and machine pool identifiers", func() {
+		// Prepare the server, the machine pool is retrieved once to import it and once more
+		// to refresh it:
+		pool := `{
+		  "id": "my-pool",
+		  "instance_type": "r5.xlarge",
+		  "replicas": 10,
+		  "labels": {
+		    "label_key1": "label_value1"
+		  }
+		}`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/machine_pools/my-pool",
+				),
+				RespondWithJSON(http.StatusOK, pool),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/machine_pools/my-pool",
+				),
+				RespondWithJSON(http.StatusOK, pool),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_machine_pool" "my_pool" {
+		    cluster      = "123"
+		    name         = "my-pool"
+		    machine_type = "r5.xlarge"
+		    replicas     = 10
+		  }
+		`)
+		Expect(terraform.Import("ocm_machine_pool.my_pool", "123,my-pool")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_machine_pool", "my_pool")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "my-pool"))
+		Expect(resource).To(MatchJQ(".attributes.name", "my-pool"))
+		Expect(resource).To(MatchJQ(".attributes.machine_type", "r5.xlarge"))
+		Expect(resource).To(MatchJQ(".attributes.replicas", float64(10)))
+		Expect(resource).To(MatchJQ(".attributes.labels.label_key1", "label_value1"))
+	})
+
+	It("Fails to import a machine pool without the cluster identifier", func() {
+		// Run the import command, no request should be sent:
+		terraform.Source(`
+		  resource "ocm_machine_pool" "my_pool" {
+		    cluster      = "123"
+		    name         = "my-pool"
+		    machine_type = "r5.xlarge"
+		    replicas     = 10
+		  }
+		`)
+		Expect(terraform.Import("ocm_machine_pool.my_pool", "my-pool")).ToNot(BeZero())
+	})
+})
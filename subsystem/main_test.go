@@ -241,6 +241,11 @@ func (r *TerraformRunner) Destroy() int {
 	return r.Run("destroy", "-auto-approve")
 }
 
+// Import runs the `import` command.
+func (r *TerraformRunner) Import(address, id string) int {
+	return r.Run("import", address, id)
+}
+
 // State returns the reads the Terraform state and returns the result of parsing
 // it as a JSON document.
 func (r *TerraformRunner) State() interface{} {
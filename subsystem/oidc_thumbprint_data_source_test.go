@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/sha1" // nolint:gosec
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("OIDC thumbprint data source", func() {
+	var issuer *httptest.Server
+	var trustedCAs string
+	var thumbprint string
+
+	BeforeEach(func() {
+		// Start a TLS server that plays the role of the OIDC issuer:
+		issuer = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		cert := issuer.Certificate()
+		trustedCAs = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Raw,
+		}))
+		hash := sha1.Sum(cert.Raw) // nolint:gosec
+		thumbprint = hex.EncodeToString(hash[:])
+	})
+
+	AfterEach(func() {
+		issuer.Close()
+	})
+
+	It("Calculates the thumbprint of a trusted issuer", func() {
+		// Run the apply command:
+		terraform.Source(fmt.Sprintf(`
+		  data "ocm_oidc_thumbprint" "my_issuer" {
+		    issuer_url  = "%s/my-cluster"
+		    trusted_cas = %s
+		    timeout     = 5
+		  }
+		`, issuer.URL, strconv.Quote(trustedCAs)))
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_oidc_thumbprint", "my_issuer")
+		Expect(resource).To(MatchJQ(".attributes.thumbprint", thumbprint))
+		Expect(resource).To(MatchJQ(".attributes.certificates | length", 1))
+		Expect(resource).To(MatchJQ(".attributes.certificates[0].sha1_fingerprint", thumbprint))
+		Expect(resource).To(MatchJQ(".attributes.certificates[0].subject", "O=Acme Co"))
+		Expect(resource).To(MatchJQ(".attributes.certificates[0].issuer", "O=Acme Co"))
+		Expect(resource).To(MatchJQ(".attributes.certificates[0].is_ca", true))
+		Expect(resource).To(MatchJQ(".attributes.certificates[0].not_after",
+			issuer.Certificate().NotAfter.UTC().Format("2006-01-02T15:04:05Z07:00")))
+	})
+
+	It("Fails if the issuer isn't trusted", func() {
+		terraform.Source(fmt.Sprintf(`
+		  data "ocm_oidc_thumbprint" "my_issuer" {
+		    issuer_url = "%s"
+		  }
+		`, issuer.URL))
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the timeout isn't positive", func() {
+		terraform.Source(fmt.Sprintf(`
+		  data "ocm_oidc_thumbprint" "my_issuer" {
+		    issuer_url  = "%s"
+		    trusted_cas = %s
+		    timeout     = 0
+		  }
+		`, issuer.URL, strconv.Quote(trustedCAs)))
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
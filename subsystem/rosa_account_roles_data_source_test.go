@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+const getStsPolicies = `{
+	"page": 1,
+	"size": 8,
+	"total": 8,
+	"items": [
+		{
+			"id": "sts_installer_trust_policy",
+			"type": "AccountRole",
+			"details": "{\"Principal\":{\"AWS\":\"arn:%{partition}:iam::%{aws_account_id}:role/RH-Managed-OpenShift-Installer\"}}"
+		},
+		{
+			"id": "sts_installer_permission_policy",
+			"type": "AccountRole",
+			"details": "{\"Action\":[\"ec2:*\"]}"
+		},
+		{
+			"id": "sts_support_trust_policy",
+			"type": "AccountRole",
+			"details": "{\"Principal\":{\"AWS\":\"arn:%{partition}:iam::%{aws_account_id}:role/RH-Technical-Support-Access\"}}"
+		},
+		{
+			"id": "sts_support_permission_policy",
+			"type": "AccountRole",
+			"details": "{\"Action\":[\"ec2:Describe*\"]}"
+		},
+		{
+			"id": "sts_instance_controlplane_trust_policy",
+			"type": "AccountRole",
+			"details": "{\"Principal\":{\"Service\":\"ec2.amazonaws.com\"}}"
+		},
+		{
+			"id": "sts_instance_controlplane_permission_policy",
+			"type": "AccountRole",
+			"details": "{\"Action\":[\"ec2:AttachVolume\"]}"
+		},
+		{
+			"id": "sts_instance_worker_trust_policy",
+			"type": "AccountRole",
+			"details": "{\"Principal\":{\"Service\":\"ec2.amazonaws.com\"}}"
+		},
+		{
+			"id": "sts_instance_worker_permission_policy",
+			"type": "AccountRole",
+			"details": "{\"Action\":[\"ec2:DescribeInstances\"]}"
+		}
+	]
+}`
+
+var _ = Describe("ROSA account IAM roles data source", func() {
+	It("Returns the account roles with the default prefix", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getStsPolicies),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_account_roles" "account_roles" {
+		    openshift_version     = "openshift-v4.12.5"
+		    redhat_aws_account_id = "710019948333"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_account_roles", "account_roles")
+		Expect(resource).To(MatchJQ(`.attributes.account_role_prefix`, "ManagedOpenShift"))
+		Expect(resource).To(MatchJQ(`.attributes.partition`, "aws"))
+		Expect(resource).To(MatchJQ(`.attributes.redhat_aws_account_id`, "710019948333"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles | length`, 4))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].role_type`, "installer"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].role_name`,
+			"ManagedOpenShift-Installer-Role"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].policy_name`,
+			"ManagedOpenShift-Installer-Role-Policy"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].trust_policy`,
+			`{"Principal":{"AWS":"arn:aws:iam::710019948333:role/RH-Managed-OpenShift-Installer"}}`))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].permission_policy`,
+			`{"Action":["ec2:*"]}`))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].tags.rosa_openshift_version`, "4.12"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].tags.rosa_role_prefix`,
+			"ManagedOpenShift"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].tags.rosa_role_type`, "installer"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[0].tags["red-hat-managed"]`, "true"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[3].role_name`,
+			"ManagedOpenShift-Worker-Role"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[3].permission_policy`,
+			`{"Action":["ec2:DescribeInstances"]}`))
+	})
+
+	It("Uses the given prefix and partition", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getStsPolicies),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_account_roles" "account_roles" {
+		    account_role_prefix   = "my-prefix"
+		    openshift_version     = "4.11"
+		    partition             = "aws-us-gov"
+		    redhat_aws_account_id = "644306948063"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_account_roles", "account_roles")
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[1].role_name`, "my-prefix-Support-Role"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[1].trust_policy`,
+			`{"Principal":{"AWS":"arn:aws-us-gov:iam::644306948063:role/RH-Technical-Support-Access"}}`))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[1].tags.rosa_openshift_version`, "4.11"))
+		Expect(resource).To(MatchJQ(`.attributes.account_roles[2].role_name`,
+			"my-prefix-ControlPlane-Role"))
+	})
+
+	It("Fails if a policy is missing", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 0,
+				  "total": 0,
+				  "items": []
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_account_roles" "account_roles" {
+		    openshift_version     = "4.12"
+		    redhat_aws_account_id = "710019948333"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the Red Hat AWS account of the environment isn't known", func() {
+		// Run the apply command, the URL of the test server isn't a known environment:
+		terraform.Source(`
+		  data "ocm_rosa_account_roles" "account_roles" {
+		    openshift_version = "4.12"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the Red Hat AWS account is invalid", func() {
+		terraform.Source(`
+		  data "ocm_rosa_account_roles" "account_roles" {
+		    openshift_version     = "4.12"
+		    redhat_aws_account_id = "7100"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the version isn't supported", func() {
+		terraform.Source(`
+		  data "ocm_rosa_account_roles" "account_roles" {
+		    openshift_version = "4.9.0"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the prefix is invalid", func() {
+		terraform.Source(`
+		  data "ocm_rosa_account_roles" "account_roles" {
+		    account_role_prefix = "my prefix"
+		    openshift_version   = "4.12"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
@@ -0,0 +1,173 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("ROSA OIDC configuration", func() {
+	It("Creates a managed OIDC configuration", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/oidc_configs"),
+				VerifyJQ(".managed", true),
+				VerifyJQ(".secret_arn", nil),
+				RespondWithJSON(http.StatusCreated, `{
+				  "id": "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+				  "managed": true,
+				  "issuer_url": "https://oidc.example.com/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_rosa_oidc_config" "my_config" {
+		    managed = true
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_oidc_config", "my_config")
+		Expect(resource).To(MatchJQ(".attributes.id", "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"))
+		Expect(resource).To(MatchJQ(".attributes.managed", true))
+		Expect(resource).To(MatchJQ(".attributes.issuer_url",
+			"https://oidc.example.com/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"))
+		Expect(resource).To(MatchJQ(".attributes.thumbprint", ""))
+
+		// Prepare the server for the destroy:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/oidc_configs/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+				),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+				  "managed": true,
+				  "issuer_url": "https://oidc.example.com/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodDelete,
+					"/api/clusters_mgmt/v1/oidc_configs/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+				),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+		)
+		Expect(terraform.Destroy()).To(BeZero())
+	})
+
+	It("Creates an unmanaged OIDC configuration", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/oidc_configs"),
+				VerifyJQ(".managed", false),
+				VerifyJQ(".secret_arn", "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret"),
+				VerifyJQ(".issuer_url", "https://my-bucket.s3.us-east-1.amazonaws.com"),
+				VerifyJQ(".installer_role_arn", "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"),
+				RespondWithJSON(http.StatusCreated, `{
+				  "id": "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+				  "managed": false,
+				  "secret_arn": "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret",
+				  "issuer_url": "https://my-bucket.s3.us-east-1.amazonaws.com",
+				  "installer_role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_rosa_oidc_config" "my_config" {
+		    managed            = false
+		    secret_arn         = "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret"
+		    issuer_url         = "https://my-bucket.s3.us-east-1.amazonaws.com"
+		    installer_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_oidc_config", "my_config")
+		Expect(resource).To(MatchJQ(".attributes.id", "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"))
+		Expect(resource).To(MatchJQ(".attributes.managed", false))
+		Expect(resource).To(MatchJQ(".attributes.secret_arn",
+			"arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret"))
+	})
+
+	It("Fails if an unmanaged OIDC configuration doesn't have a secret", func() {
+		// Run the apply command, the configuration should not be requested:
+		terraform.Source(`
+		  resource "ocm_rosa_oidc_config" "my_config" {
+		    managed            = false
+		    issuer_url         = "https://my-bucket.s3.us-east-1.amazonaws.com"
+		    installer_role_arn = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if a managed OIDC configuration has an issuer URL", func() {
+		// Run the apply command, the configuration should not be requested:
+		terraform.Source(`
+		  resource "ocm_rosa_oidc_config" "my_config" {
+		    managed    = true
+		    issuer_url = "https://my-bucket.s3.us-east-1.amazonaws.com"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Imports an OIDC configuration", func() {
+		// Prepare the server:
+		server.RouteToHandler(
+			http.MethodGet,
+			"/api/clusters_mgmt/v1/oidc_configs/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+			RespondWithJSON(http.StatusOK, `{
+			  "id": "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+			  "managed": true,
+			  "issuer_url": "https://oidc.example.com/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"
+			}`),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_rosa_oidc_config" "my_config" {
+		    managed = true
+		  }
+		`)
+		Expect(terraform.Import(
+			"ocm_rosa_oidc_config.my_config", "23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1",
+		)).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_oidc_config", "my_config")
+		Expect(resource).To(MatchJQ(".attributes.managed", true))
+		Expect(resource).To(MatchJQ(".attributes.issuer_url",
+			"https://oidc.example.com/23f6gk5b6l4ps44a5a7ab3bu0hjqg5d1"))
+	})
+})
@@ -46,6 +46,29 @@ const (
 		}
 	]
 }`
+
+	getOperatorStsPolicies = `{
+	"page": 1,
+	"size": 3,
+	"total": 3,
+	"items": [
+		{
+			"id": "operator_iam_role_policy",
+			"type": "OperatorRole",
+			"details": "{\"Principal\":{\"Federated\":\"%{oidc_provider_arn}\"},\"Condition\":{\"StringEquals\":{\"%{issuer_url}:sub\":[\"%{service_accounts}\"]}}}"
+		},
+		{
+			"id": "openshift_cluster_csi_drivers_ebs_cloud_credentials_policy",
+			"type": "OperatorRole",
+			"details": "{\"Action\":[\"ec2:AttachVolume\"],\"Resource\":\"arn:%{partition}:ec2:*\"}"
+		},
+		{
+			"id": "openshift_cloud_network_config_controller_cloud_credentials_policy",
+			"type": "OperatorRole",
+			"details": "{\"Action\":[\"ec2:AssignPrivateIpAddresses\"]}"
+		}
+	]
+}`
 )
 
 var _ = Describe("ROSA Operator IAM roles data source", func() {
@@ -57,6 +80,10 @@ var _ = Describe("ROSA Operator IAM roles data source", func() {
 				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
 				RespondWithJSON(http.StatusOK, getStsCredentialRequests),
 			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getOperatorStsPolicies),
+			),
 		)
 
 		// Run the apply command:
@@ -112,6 +139,10 @@ var _ = Describe("ROSA Operator IAM roles data source", func() {
 				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
 				RespondWithJSON(http.StatusOK, getStsCredentialRequests),
 			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getOperatorStsPolicies),
+			),
 		)
 
 		// Run the apply command:
@@ -158,6 +189,297 @@ var _ = Describe("ROSA Operator IAM roles data source", func() {
 			[]string{"system:serviceaccount:openshift-cloud-network-config-controller:cloud-network-config-controller"},
 		)
 	})
+	It("Renders the policies of the operator roles", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusOK, getStsCredentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getOperatorStsPolicies),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    operator_role_prefix = "my-cluster"
+		    oidc_endpoint_url    = "https://oidc.example.com/my-cluster"
+		    aws_account_id       = "123456789012"
+		    partition            = "aws-us-gov"
+		    path                 = "/rosa/"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state, the roles are sorted by namespace:
+		resource := terraform.Resource("ocm_rosa_operator_roles", "operator_roles")
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].operator_namespace`,
+			"openshift-cloud-network-config-controller"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].role_name`,
+			"my-cluster-openshift-cluster-csi-drivers-ebs-cloud-credentials"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].role_arn`,
+			"arn:aws-us-gov:iam::123456789012:role/rosa/my-cluster-openshift-cluster-csi-drivers-ebs-cloud-credentials"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].trust_policy`,
+			`{"Principal":{"Federated":"arn:aws-us-gov:iam::123456789012:oidc-provider/oidc.example.com/my-cluster"},`+
+				`"Condition":{"StringEquals":{"oidc.example.com/my-cluster:sub":[`+
+				`"system:serviceaccount:openshift-cluster-csi-drivers:aws-ebs-csi-driver-operator" , `+
+				`"system:serviceaccount:openshift-cluster-csi-drivers:aws-ebs-csi-driver-controller-sa"]}}}`))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].permission_policy`,
+			`{"Action":["ec2:AttachVolume"],"Resource":"arn:aws-us-gov:ec2:*"}`))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].permission_policy`,
+			`{"Action":["ec2:AssignPrivateIpAddresses"]}`))
+	})
+
+	It("Doesn't render the trust policies without the OIDC endpoint URL", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusOK, getStsCredentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getOperatorStsPolicies),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    operator_role_prefix = "my-cluster"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_operator_roles", "operator_roles")
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].trust_policy`, nil))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].role_arn`, nil))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].attached`, nil))
+		Expect(resource).To(MatchJQ(`.attributes.cluster_operator_iam_roles`, nil))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].permission_policy`,
+			`{"Action":["ec2:AttachVolume"],"Resource":"arn:aws:ec2:*"}`))
+	})
+
+	It("Fails if the path is invalid", func() {
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    operator_role_prefix = "my-cluster"
+		    path                 = "rosa"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+	It("Returns only the operator roles needed by the version", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusOK, getStsCredentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getOperatorStsPolicies),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    operator_role_prefix = "my-cluster"
+		    openshift_version    = "openshift-v4.9.59"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_operator_roles", "operator_roles")
+		Expect(resource).To(MatchJQ(`.attributes.openshift_version`, "openshift-v4.9.59"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles | length`, 1))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].operator_namespace`,
+			"openshift-cluster-csi-drivers"))
+	})
+
+	It("Takes the version and prefixes from the cluster", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "version": {
+				    "id": "openshift-v4.9.59",
+				    "raw_id": "4.9.59"
+				  },
+				  "aws": {
+				    "sts": {
+				      "role_arn": "arn:aws:iam::123456789012:role/my-account-Installer-Role",
+				      "operator_iam_roles": [
+				        {
+				          "name": "ebs-cloud-credentials",
+				          "namespace": "openshift-cluster-csi-drivers",
+				          "role_arn": "arn:aws:iam::123456789012:role/my-cluster-openshift-cluster-csi-drivers-ebs-cloud-credentials"
+				        }
+				      ]
+				    }
+				  }
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusOK, getStsCredentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getOperatorStsPolicies),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_rosa_operator_roles", "operator_roles")
+		Expect(resource).To(MatchJQ(`.attributes.openshift_version`, "4.9.59"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_role_prefix`, "my-cluster"))
+		Expect(resource).To(MatchJQ(`.attributes.account_role_prefix`, "my-account"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles | length`, 1))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].role_name`,
+			"my-cluster-openshift-cluster-csi-drivers-ebs-cloud-credentials"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].policy_name`,
+			"my-account-openshift-cluster-csi-drivers-ebs-cloud-credentials"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].attached`, true))
+	})
+
+	It("Flags the operators that don't have a role attached in the cluster", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "version": {
+				    "id": "openshift-v4.12.5",
+				    "raw_id": "4.12.5"
+				  },
+				  "aws": {
+				    "sts": {
+				      "operator_role_prefix": "my-cluster",
+				      "operator_iam_roles": [
+				        {
+				          "name": "ebs-cloud-credentials",
+				          "namespace": "openshift-cluster-csi-drivers",
+				          "role_arn": "arn:aws:iam::123456789012:role/rosa/my-custom-role",
+				          "service_account": "aws-ebs-csi-driver-operator"
+				        }
+				      ]
+				    }
+				  }
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusOK, getStsCredentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_policies"),
+				RespondWithJSON(http.StatusOK, getOperatorStsPolicies),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state, the roles are sorted by namespace:
+		resource := terraform.Resource("ocm_rosa_operator_roles", "operator_roles")
+		Expect(resource).To(MatchJQ(`.attributes.operator_role_prefix`, "my-cluster"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles | length`, 2))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].operator_namespace`,
+			"openshift-cloud-network-config-controller"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].attached`, false))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[0].role_arn`, nil))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].attached`, true))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].role_name`, "my-custom-role"))
+		Expect(resource).To(MatchJQ(`.attributes.operator_iam_roles[1].role_arn`,
+			"arn:aws:iam::123456789012:role/rosa/my-custom-role"))
+		Expect(resource).To(MatchJQ(`.attributes.cluster_operator_iam_roles | length`, 1))
+		Expect(resource).To(MatchJQ(`.attributes.cluster_operator_iam_roles[0].operator_name`,
+			"ebs-cloud-credentials"))
+		Expect(resource).To(MatchJQ(`.attributes.cluster_operator_iam_roles[0].operator_namespace`,
+			"openshift-cluster-csi-drivers"))
+		Expect(resource).To(MatchJQ(`.attributes.cluster_operator_iam_roles[0].service_account`,
+			"aws-ebs-csi-driver-operator"))
+	})
+
+	It("Fails if the cluster doesn't exist", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusNotFound, `{
+				  "kind": "Error",
+				  "id": "404",
+				  "href": "/api/clusters_mgmt/v1/errors/404",
+				  "code": "CLUSTERS-MGMT-404",
+				  "reason": "Cluster '123' not found"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the credential requests can't be listed", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusForbidden, `{
+				  "kind": "Error",
+				  "id": "403",
+				  "href": "/api/clusters_mgmt/v1/errors/403",
+				  "code": "CLUSTERS-MGMT-403",
+				  "reason": "Forbidden"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    operator_role_prefix = "my-cluster"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails without operator role prefix and cluster", func() {
+		terraform.Source(`
+		  data "ocm_rosa_operator_roles" "operator_roles" {
+		    account_role_prefix = "my-account"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
 })
 
 func compareResultOfRoles(resource interface{}, index int, name, namespace, policyName, roleName string, serviceAccountLen int, serviceAccounts []string) {